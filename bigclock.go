@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// bigDigits is a block font for the zoomed clock, five rows per glyph.
+var bigDigits = map[rune][5]string{
+	'0': {"█████", "█   █", "█   █", "█   █", "█████"},
+	'1': {"  █  ", " ██  ", "  █  ", "  █  ", " ███ "},
+	'2': {"█████", "    █", "█████", "█    ", "█████"},
+	'3': {"█████", "    █", " ████", "    █", "█████"},
+	'4': {"█   █", "█   █", "█████", "    █", "    █"},
+	'5': {"█████", "█    ", "█████", "    █", "█████"},
+	'6': {"█████", "█    ", "█████", "█   █", "█████"},
+	'7': {"█████", "    █", "   █ ", "  █  ", "  █  "},
+	'8': {"█████", "█   █", "█████", "█   █", "█████"},
+	'9': {"█████", "█   █", "█████", "    █", "█████"},
+	':': {"   ", " █ ", "   ", " █ ", "   "},
+}
+
+// bigText renders s ("12:34") in the block font. Other characters are
+// skipped.
+func bigText(s string) string {
+	var rows [5]strings.Builder
+	for _, r := range s {
+		glyph, ok := bigDigits[r]
+		if !ok {
+			continue
+		}
+		for i, line := range glyph {
+			rows[i].WriteString(line + " ")
+		}
+	}
+	lines := make([]string, len(rows))
+	for i := range rows {
+		lines[i] = strings.TrimRight(rows[i].String(), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// clockFace formats d as MM:SS, or H:MM:SS from an hour up.
+func clockFace(d time.Duration) string {
+	s := int(d.Round(time.Second) / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
+
+// zoomedTimer is the timer shown by z, or nil outside zoom or once it is
+// deleted.
+func (m model) zoomedTimer() *Timer {
+	for _, t := range m.timers {
+		if t.ID == m.zoomID {
+			return t
+		}
+	}
+	return nil
+}
+
+// viewZoom fills the screen with one timer's time in big digits, its name
+// and state underneath.
+func (m model) viewZoom(t *Timer) string {
+	d, status := t.Remaining, ""
+	switch {
+	case t.Finished:
+		d, status = 0, m.text.TimesUp
+	case t.CountUp:
+		d = t.Elapsed
+		if !t.Running {
+			status = m.text.Paused
+		}
+	case t.Queued:
+		status = m.text.Queued
+	case t.Waiting:
+		status = m.text.Waiting
+	case !t.Running:
+		status = m.text.Paused
+	}
+
+	clock := bigText(clockFace(d))
+	if t.Alarming && m.cfg.noBlink() {
+		clock = m.styles.AlarmSolid.Render(clock)
+	} else if t.Alarming && m.blink {
+		clock = m.styles.Alarm.Render(clock)
+	}
+	caption := m.timerName(t)
+	if status != "" {
+		caption += " — " + status
+	}
+	content := lipgloss.JoinVertical(lipgloss.Center, clock, "", caption, "", m.styles.Muted.Render(m.text.ZoomBack))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardMsg reports how copying to the clipboard went.
+type clipboardMsg struct {
+	Text string
+	Err  error
+}
+
+// errClipboardUnavailable means no clipboard command was found.
+var errClipboardUnavailable = errors.New("no clipboard command found")
+
+// clipboardCommands are the commands that copy their stdin to the system
+// clipboard, best first.
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	return [][]string{
+		{"wl-copy"},
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+	}
+}
+
+// copyCmd puts text on the clipboard with the first command that is
+// installed, the way playSound works through its players.
+func copyCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		for _, args := range clipboardCommands() {
+			if _, err := exec.LookPath(args[0]); err != nil {
+				continue
+			}
+			cmd := exec.Command(args[0], args[1:]...)
+			cmd.Stdin = strings.NewReader(text)
+			return clipboardMsg{Text: text, Err: cmd.Run()}
+		}
+		return clipboardMsg{Text: text, Err: errClipboardUnavailable}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// commandResultMsg reports how a timer's on-finish command exited.
+type commandResultMsg struct {
+	ID  int
+	Err error
+}
+
+// runFinishCommand runs a timer's attached command through the shell.
+func runFinishCommand(id int, command string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", command)
+		} else {
+			cmd = exec.Command("sh", "-c", command)
+		}
+		return commandResultMsg{ID: id, Err: cmd.Run()}
+	}
+}
+
+// commandLabel derives a short label from a command line: the program name
+// plus its first argument when that is a plain word ("make build").
+func commandLabel(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return ""
+	}
+	label := filepath.Base(fields[0])
+	if len(fields) > 1 && isPlainWord(fields[1]) {
+		label += " " + fields[1]
+	}
+	return label
+}
+
+func isPlainWord(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return !strings.HasPrefix(s, "-")
+}
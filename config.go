@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// config holds user settings read from config.json. Zero values keep the
+// built-in defaults, so a missing file behaves exactly like an empty one.
+type config struct {
+	// GracePeriod is the final stretch during which a timer shows
+	// "finishing..." before the alarm actually fires.
+	GracePeriod configDuration `json:"grace_period"`
+
+	// Locale names a file in locales/ that translates the UI text.
+	Locale string `json:"locale"`
+
+	// Direction is "ltr" (default) or "rtl" to mirror the layout.
+	Direction string `json:"direction"`
+
+	// BlinkInterval is the alarm blink period (default 500ms). NoBlink
+	// replaces the blink with a steady highlight, as does setting NO_FLASH.
+	BlinkInterval configDuration `json:"blink_interval"`
+	NoBlink       bool           `json:"no_blink"`
+
+	// Volume is the alarm volume in percent, 1 to 100 (default 100). It
+	// only applies where the sound player supports it.
+	Volume int `json:"volume"`
+
+	// FlashScreen flashes the whole screen red with the blink while an
+	// alarm rings. Off by default.
+	FlashScreen bool `json:"flash_screen"`
+
+	// ShowStarted appends each timer's creation time to its row.
+	ShowStarted bool `json:"show_started"`
+
+	// MaxRunning caps how many timers count down at once (0 = no limit).
+	// Extra timers wait, paused, and start as running ones finish.
+	MaxRunning int `json:"max_running"`
+
+	// MaxTimers caps how many timers the list holds (default 50, -1 = no
+	// limit). Adding past it shows a message instead.
+	MaxTimers int `json:"max_timers"`
+
+	// WarnDuplicates asks before adding a timer with the same duration
+	// and label as one that is still running.
+	WarnDuplicates bool `json:"warn_duplicates"`
+
+	// BatchSound decides what happens when several timers finish on the
+	// same tick: "combined" (default) plays one sound, "count" plays one
+	// sound and announces how many finished, "sequential" plays one sound
+	// per timer.
+	BatchSound string `json:"batch_sound"`
+
+	// AllowCommands enables "!command" on timers, run through the shell
+	// when the timer finishes. Off by default.
+	AllowCommands bool `json:"allow_commands"`
+
+	// WebhookURL receives a JSON POST for every finished timer.
+	WebhookURL string `json:"webhook_url"`
+
+	// SafeMode turns off everything that reaches outside the app: on-finish
+	// commands and webhooks.
+	SafeMode bool `json:"safe_mode"`
+
+	// LabelTemplate names unlabeled timers; "{n}" becomes the timer ID.
+	LabelTemplate string `json:"label_template"`
+
+	// SoundFile is played for the alarm instead of the system sounds.
+	// TUI_TIMER_SOUND overrides it.
+	SoundFile string `json:"sound_file"`
+
+	// AnnounceCommand is a text-to-speech command ("espeak", "say") run
+	// after the alarm sound with "<timer> is done" as its last argument.
+	AnnounceCommand string `json:"announce_command"`
+
+	// Layout is "top" (default) or "bottom" to pin the input and buttons
+	// under the list, chat-style.
+	Layout string `json:"layout"`
+
+	// Snooze is how long s puts a finished timer off for (default 5m).
+	Snooze configDuration `json:"snooze"`
+
+	// WindowTitle puts a countdown in the terminal title: "nearest" for
+	// the timer that finishes soonest, "selected" for the highlighted one.
+	// Off when empty.
+	WindowTitle string `json:"window_title"`
+
+	// RemainingWords starts with remaining time shown in words ("about 5
+	// minutes left") instead of exact; w toggles it either way.
+	RemainingWords bool `json:"remaining_words"`
+
+	// QuitKey decides what "q" does: "quit" (default) exits at once unless
+	// timers are unfinished, "confirm" always asks first and "off" leaves
+	// only ctrl+c to quit. q is never a command while typing in the input.
+	QuitKey string `json:"quit_key"`
+
+	// Keys rebinds actions, e.g. {"quit": ["Q"], "add": ["a"]}. See
+	// keyMap.bindings for the action names.
+	Keys map[string][]string `json:"keys"`
+
+	// RemoveFinished drops finished timers from the list this long after
+	// their alarm is dismissed. Off when zero, keeping them as a log.
+	RemoveFinished configDuration `json:"remove_finished"`
+
+	// Buttons adds quick-add buttons after Quit; each one starts a timer
+	// with its duration and label.
+	Buttons []buttonConfig `json:"buttons"`
+
+	// Theme is "dark" or "light"; empty picks the one that suits the
+	// terminal's background. Styles apply on top of it.
+	Theme string `json:"theme"`
+
+	// Styles restyles individual screen regions.
+	Styles styleConfig `json:"styles"`
+}
+
+const defaultBlinkInterval = 500 * time.Millisecond
+
+func (c config) blinkInterval() time.Duration {
+	if c.BlinkInterval <= 0 {
+		return defaultBlinkInterval
+	}
+	return time.Duration(c.BlinkInterval)
+}
+
+const defaultMaxTimers = 50
+
+// maxTimers is the timer cap, or 0 for none.
+func (c config) maxTimers() int {
+	switch {
+	case c.MaxTimers < 0:
+		return 0
+	case c.MaxTimers == 0:
+		return defaultMaxTimers
+	}
+	return c.MaxTimers
+}
+
+// noFlashEnv turns blinking off like no_blink when set to anything, for
+// users who need reduced motion everywhere.
+const noFlashEnv = "NO_FLASH"
+
+func (c config) noBlink() bool {
+	return c.NoBlink || os.Getenv(noFlashEnv) != ""
+}
+
+// buttonConfig is a quick-add button, shown as "Label Duration".
+type buttonConfig struct {
+	Label     string         `json:"label"`
+	Duration  configDuration `json:"duration"`
+	SoundFile string         `json:"sound_file"`
+}
+
+func (b buttonConfig) title() string {
+	return strings.TrimSpace(b.Label + " " + time.Duration(b.Duration).String())
+}
+
+// volumeStep is how far + and - move the volume.
+const volumeStep = 10
+
+func (c config) volume() int {
+	if c.Volume <= 0 {
+		return 100
+	}
+	return min(100, c.Volume)
+}
+
+const defaultSnooze = 5 * time.Minute
+
+func (c config) snooze() time.Duration {
+	if c.Snooze <= 0 {
+		return defaultSnooze
+	}
+	return time.Duration(c.Snooze)
+}
+
+func (c config) commandsAllowed() bool {
+	return c.AllowCommands && !c.SafeMode
+}
+
+func (c config) rtl() bool {
+	return c.Direction == "rtl"
+}
+
+// configDuration is a time.Duration written as a Go duration string
+// ("5s", "1m30s") in the config file.
+type configDuration time.Duration
+
+func (d *configDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = configDuration(parsed)
+	return nil
+}
+
+// configDir returns $XDG_CONFIG_HOME/tui-timer, falling back to
+// ~/.config/tui-timer.
+func configDir() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "tui-timer")
+}
+
+func loadConfig() (config, error) {
+	var cfg config
+	dir := configDir()
+	if dir == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	if _, err := newKeyMap(cfg.Keys); err != nil {
+		return cfg, err
+	}
+	if _, ok := themes[cfg.Theme]; cfg.Theme != "" && !ok {
+		return cfg, fmt.Errorf("unknown theme %q", cfg.Theme)
+	}
+	for _, b := range cfg.Buttons {
+		if b.Duration <= 0 {
+			return cfg, fmt.Errorf("button %q needs a duration", b.Label)
+		}
+	}
+	return cfg, nil
+}
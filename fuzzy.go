@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var fuzzyNumbers = map[string]float64{
+	"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+	"eleven": 11, "twelve": 12, "thirteen": 13, "fourteen": 14, "fifteen": 15,
+	"sixteen": 16, "seventeen": 17, "eighteen": 18, "nineteen": 19,
+	"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+	"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+	"couple": 2, "few": 3,
+}
+
+var fuzzyUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+}
+
+// parseFuzzyDuration understands loose phrases such as "five mins",
+// "quarter hour", "half an hour", "twenty-five minutes" or "an hour and a
+// half". It is the fallback when time.ParseDuration rejects the input.
+func parseFuzzyDuration(input string) (time.Duration, error) {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(input, "-", " ")))
+	if len(words) == 0 {
+		return 0, errors.New("empty duration")
+	}
+
+	var (
+		total    time.Duration
+		num      float64
+		hasNum   bool
+		article  bool // num came from "a"/"an"
+		and      bool // "and" since the last number
+		lastUnit time.Duration
+	)
+	for _, w := range words {
+		if n, ok := fuzzyNumbers[w]; ok {
+			w = strconv.FormatFloat(n, 'f', -1, 64)
+		}
+		if n, err := strconv.ParseFloat(w, 64); err == nil {
+			switch {
+			case !hasNum || article:
+				num = n
+			case num >= 20 && int(num)%10 == 0 && n < 10:
+				num += n // "twenty five"
+			default:
+				return 0, fmt.Errorf("unexpected %q", w)
+			}
+			hasNum, article = true, false
+			continue
+		}
+		if unit, ok := fuzzyUnits[w]; ok {
+			if !hasNum {
+				num = 1
+			}
+			total += time.Duration(num * float64(unit))
+			hasNum, article, and = false, false, false
+			lastUnit = unit
+			continue
+		}
+
+		switch w {
+		case "a", "an":
+			if !hasNum {
+				num, hasNum, article = 1, true, true
+			}
+		case "half", "quarter", "quarters":
+			part := 0.5
+			if w != "half" {
+				part = 0.25
+			}
+			switch {
+			case !hasNum || article:
+				num = part // "half an hour", "a quarter hour"
+			case and:
+				num += part // "one and a half hours"
+			default:
+				num *= part // "three quarters of an hour"
+			}
+			hasNum, article = true, false
+		case "and":
+			and = true
+		case "of":
+		default:
+			return 0, fmt.Errorf("unknown word %q", w)
+		}
+	}
+
+	if article {
+		// "5 mins a" is five minutes and the start of a label, not six
+		return 0, errors.New("article without a unit")
+	}
+	if hasNum {
+		// A trailing "and a half" applies to the last unit seen
+		if lastUnit == 0 {
+			return 0, errors.New("missing unit")
+		}
+		total += time.Duration(num * float64(lastUnit))
+	}
+	if total <= 0 {
+		return 0, errors.New("duration must be positive")
+	}
+	return total, nil
+}
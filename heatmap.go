@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	heatmapBucket  = 15 * time.Minute
+	heatmapBuckets = 12 // Rows shown, newest last
+	heatmapMaxBar  = 30
+)
+
+// heatColors shade a bucket by how many timers finished in it.
+var heatColors = []lipgloss.Color{"22", "28", "34", "40", "46"}
+
+// renderHeatmap draws one row per 15-minute bucket of this session's
+// finishes, ending at now, or the empty message when there are none.
+func renderHeatmap(finishes []time.Time, now time.Time, st styles, empty string) string {
+	if len(finishes) == 0 {
+		return st.Muted.Render(empty)
+	}
+
+	last := now.Truncate(heatmapBucket)
+	first := finishes[0].Truncate(heatmapBucket)
+	if earliest := last.Add(-heatmapBucket * (heatmapBuckets - 1)); first.Before(earliest) {
+		first = earliest
+	}
+
+	counts := map[time.Time]int{}
+	for _, f := range finishes {
+		counts[f.Truncate(heatmapBucket)]++
+	}
+
+	var rows []string
+	for b := first; !b.After(last); b = b.Add(heatmapBucket) {
+		n := counts[b]
+		bar := strings.Repeat("█", min(n, heatmapMaxBar))
+		if n > 0 {
+			bar = lipgloss.NewStyle().Foreground(heatColors[min(n, len(heatColors))-1]).Render(bar)
+		}
+		rows = append(rows, fmt.Sprintf("%s %s %s", st.Muted.Render(b.Format("15:04")), bar, st.Muted.Render(fmt.Sprint(n))))
+	}
+	return strings.Join(rows, "\n")
+}
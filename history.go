@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyEntry is one line of history.jsonl, written when a timer finishes.
+type historyEntry struct {
+	Session  string    `json:"session,omitempty"`
+	Label    string    `json:"label,omitempty"`
+	Duration string    `json:"duration"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// stateDir returns $XDG_STATE_HOME/tui-timer, falling back to
+// ~/.local/state/tui-timer.
+func stateDir() string {
+	base := os.Getenv("XDG_STATE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(base, "tui-timer")
+}
+
+func historyPath() string {
+	return filepath.Join(stateDir(), "history.jsonl")
+}
+
+// newSessionID names one run of the app in the history, e.g.
+// "20260102-150405-4321": its start time and process ID.
+func newSessionID(now time.Time) string {
+	return fmt.Sprintf("%s-%d", now.Format("20060102-150405"), os.Getpid())
+}
+
+func newHistoryEntry(t *Timer, finished time.Time, session string) historyEntry {
+	return historyEntry{
+		Session:  session,
+		Label:    t.Label,
+		Duration: t.Duration.String(),
+		Started:  t.began(),
+		Finished: finished,
+	}
+}
+
+// recordHistory appends entries to the history log. Failures are ignored so
+// a read-only state directory never interrupts the alarm.
+func recordHistory(entries []historyEntry) tea.Cmd {
+	return func() tea.Msg {
+		_ = appendHistory(historyPath(), entries)
+		return nil
+	}
+}
+
+func appendHistory(path string, entries []historyEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readHistory(path string) ([]historyEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []historyEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// exportHistoryCSV converts the history log into a CSV file at dest.
+func exportHistoryCSV(dest string) error {
+	entries, err := readHistory(historyPath())
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"session", "label", "duration", "started", "finished"})
+	for _, e := range entries {
+		_ = w.Write([]string{
+			e.Session,
+			e.Label,
+			e.Duration,
+			e.Started.Format(time.RFC3339),
+			e.Finished.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timerSpec is what the user asked for in the input field.
+type timerSpec struct {
+	Duration  time.Duration
+	Label     string
+	Priority  Priority
+	PauseAt   time.Duration
+	Command   string // Shell command run when the timer finishes
+	CountUp   bool   // Stopwatch instead of a countdown
+	Pomodoro  bool   // Work/break cycle, labeled by its phase
+	SoundFile string // Alarm sound for just this timer
+	Group     string // Named section to list the timer under
+	Repeat    int    // Extra runs: 0 = none, -1 = forever
+}
+
+// parseTimerDuration reads a Go duration ("1h30m"), a bare number of
+// seconds ("30") or a clock-style "M:SS" / "H:MM:SS". The leading clock
+// field may be any size ("90:00"); the rest must be under 60.
+func parseTimerDuration(s string) (time.Duration, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n > math.MaxInt64/int(time.Second) {
+			return 0, fmt.Errorf("invalid duration %q: too long", s)
+		}
+		return time.Duration(n) * time.Second, nil
+	}
+	if parts := strings.Split(s, ":"); len(parts) == 2 || len(parts) == 3 {
+		var d time.Duration
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil || strings.ContainsAny(p, "+-") {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			if i > 0 && n >= 60 {
+				return 0, fmt.Errorf("invalid duration %q: %d is not under 60", s, n)
+			}
+			if n > math.MaxInt64/int(time.Second) {
+				return 0, fmt.Errorf("invalid duration %q: too long", s)
+			}
+			d = d*60 + time.Duration(n)
+		}
+		if d > math.MaxInt64/time.Second {
+			return 0, fmt.Errorf("invalid duration %q: too long", s)
+		}
+		return d * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// parseSequenceInput reads "seq: <spec>, <spec>, ...". ok is false when the
+// input is not a sequence at all.
+func parseSequenceInput(input string) (specs []timerSpec, ok bool, err error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(input), "seq:")
+	if !ok {
+		return nil, false, nil
+	}
+	for _, step := range strings.Split(rest, ",") {
+		spec, err := parseTimerInput(step)
+		if err != nil {
+			return nil, true, err
+		}
+		if spec.Repeat != 0 || spec.Pomodoro {
+			// Either would restart the step while the next one runs
+			return nil, true, errors.New("sequence steps cannot repeat")
+		}
+		if spec.Duration <= 0 {
+			// A stopwatch never finishes, so the next step would never start
+			return nil, true, errors.New("sequence steps need a duration")
+		}
+		specs = append(specs, spec)
+	}
+	return specs, true, nil
+}
+
+// parseSetInput reads "set <duration>", which sets the selected timer's
+// remaining time. ok is false when the input is not a set command.
+func parseSetInput(input string) (d time.Duration, ok bool, err error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(input), "set ")
+	if !ok {
+		return 0, false, nil
+	}
+	d, err = parseTimerDuration(strings.TrimSpace(rest))
+	if err != nil {
+		return 0, true, err
+	}
+	if d <= 0 {
+		return 0, true, errors.New("duration must be positive")
+	}
+	return d, true, nil
+}
+
+// parseTimerInput reads "<duration> [label] [prio:high|normal|low]
+// [pause:<duration>] [repeat:<n>|forever] [sound:<file>] [group:<name>]
+// [!command]". Words that are not options make up the
+// label, and everything after " !" is the on-finish command.
+func parseTimerInput(input string) (timerSpec, error) {
+	var spec timerSpec
+
+	if before, command, ok := strings.Cut(input, " !"); ok {
+		spec.Command = strings.TrimSpace(command)
+		if spec.Command == "" {
+			return spec, errors.New("empty command")
+		}
+		input = before
+	}
+
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return spec, errors.New("empty input")
+	}
+
+	if fields[0] == "up" {
+		// A stopwatch: the rest is its label and options
+		spec.CountUp = true
+		return spec, parseTimerOptions(&spec, fields[1:])
+	}
+
+	if fields[0] == "pomo" {
+		spec.Pomodoro = true
+		spec.Duration = pomodoroWork
+		if err := parseTimerOptions(&spec, fields[1:]); err != nil {
+			return spec, err
+		}
+		if spec.Label != "" {
+			return spec, errors.New("a pomodoro is labeled by its phase")
+		}
+		return spec, nil
+	}
+
+	d, n, err := leadingDuration(fields)
+	if err != nil {
+		return spec, err
+	}
+	if d <= 0 {
+		return spec, errors.New("duration must be positive")
+	}
+	spec.Duration = d
+	return spec, parseTimerOptions(&spec, fields[n:])
+}
+
+// leadingDuration reads the duration at the start of fields and reports
+// how many words it took. The longest run of words that reads as a loose
+// phrase wins, so "5 mins tea" is five minutes labeled "tea" rather than
+// five seconds labeled "mins tea".
+func leadingDuration(fields []string) (time.Duration, int, error) {
+	for n := len(fields); n > 1; n-- {
+		if d, err := parseFuzzyDuration(strings.Join(fields[:n], " ")); err == nil {
+			return d, n, nil
+		}
+	}
+	d, err := parseTimerDuration(fields[0])
+	if err != nil {
+		if fuzzy, fuzzyErr := parseFuzzyDuration(fields[0]); fuzzyErr == nil {
+			return fuzzy, 1, nil // A lone word like "hour"
+		}
+		return 0, 0, err
+	}
+	return d, 1, nil
+}
+
+// parseTimerOptions fills spec from the words after its duration.
+func parseTimerOptions(spec *timerSpec, fields []string) error {
+	var label []string
+	for _, f := range fields {
+		key, value, ok := strings.Cut(f, ":")
+		if !ok {
+			key = "" // A plain word is part of the label
+		}
+		switch key {
+		case "prio", "priority":
+			p, err := parsePriority(value)
+			if err != nil {
+				return err
+			}
+			spec.Priority = p
+		case "pause":
+			at, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			if at <= 0 || at >= spec.Duration {
+				return fmt.Errorf("checkpoint %s must be inside the timer", at)
+			}
+			spec.PauseAt = at
+		case "repeat":
+			if value == "forever" {
+				spec.Repeat = -1
+				break
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("repeat %q must be a positive count or forever", value)
+			}
+			spec.Repeat = n
+		case "sound":
+			if value == "" {
+				return errors.New("empty sound file")
+			}
+			spec.SoundFile = value
+		case "group":
+			if value == "" {
+				return errors.New("empty group name")
+			}
+			spec.Group = value
+		default:
+			label = append(label, f)
+		}
+	}
+	spec.Label = strings.Join(label, " ")
+	return nil
+}
+
+func parsePriority(s string) (Priority, error) {
+	switch strings.ToLower(s) {
+	case "high", "h":
+		return HIGH, nil
+	case "normal", "n", "":
+		return NORMAL, nil
+	case "low", "l":
+		return LOW, nil
+	}
+	return NORMAL, fmt.Errorf("unknown priority %q", s)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimerDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"0:30", 30 * time.Second},
+		{"1:05", time.Minute + 5*time.Second},
+		{"90:00", 90 * time.Minute},
+		{"1:30:45", time.Hour + 30*time.Minute + 45*time.Second},
+		{"0:00:10", 10 * time.Second},
+		{"45", 45 * time.Second},
+		{"1h30m", 90 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := parseTimerDuration(tt.in)
+		if err != nil {
+			t.Errorf("parseTimerDuration(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseTimerDuration(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimerDurationRejects(t *testing.T) {
+	for _, in := range []string{
+		"1:2:3:4",        // Too many fields
+		"1:60",           // Seconds not under 60
+		"1:75:00",        // Minutes not under 60
+		"1:-5",           // Negative
+		"1:+5",           // Signed
+		":30",            // Empty field
+		"1:3o",           // Not a number
+		"1::30",          // Empty middle field
+		"five",           // Not a duration at all
+		"99999999999999", // Overflows time.Duration
+		"9999999999:00",  // So does its clock form
+	} {
+		if d, err := parseTimerDuration(in); err == nil {
+			t.Errorf("parseTimerDuration(%q) = %s, want an error", in, d)
+		}
+	}
+}
+
+func TestParseSequenceRejectsRepeats(t *testing.T) {
+	for _, in := range []string{"seq: 1m repeat:2, 2m", "seq: 5m, pomo", "seq: up, 5m"} {
+		if _, _, err := parseSequenceInput(in); err == nil {
+			t.Errorf("parseSequenceInput(%q) accepted a step that cannot run in turn", in)
+		}
+	}
+}
+
+func TestParseTimerInputPhrases(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  time.Duration
+		label string
+	}{
+		{"5 mins", 5 * time.Minute, ""},
+		{"10 minutes", 10 * time.Minute, ""},
+		{"1 hour", time.Hour, ""},
+		{"ten minutes tea", 10 * time.Minute, "tea"},
+		{"5 mins quick tea", 5 * time.Minute, "quick tea"},
+		{"5m tea", 5 * time.Minute, "tea"},
+		{"30 eggs", 30 * time.Second, "eggs"},
+		{"5 mins a walk", 5 * time.Minute, "a walk"},
+		{"ten minutes an essay", 10 * time.Minute, "an essay"},
+		{"half an hour a break", 30 * time.Minute, "a break"},
+	}
+	for _, tt := range tests {
+		spec, err := parseTimerInput(tt.in)
+		if err != nil {
+			t.Errorf("parseTimerInput(%q): %v", tt.in, err)
+			continue
+		}
+		if spec.Duration != tt.want || spec.Label != tt.label {
+			t.Errorf("parseTimerInput(%q) = %s %q, want %s %q", tt.in, spec.Duration, spec.Label, tt.want, tt.label)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// keyMap is every key binding. The defaults can be changed per action with
+// "keys" in the config file, e.g. "keys": {"quit": ["Q"], "add": ["a"]}.
+type keyMap struct {
+	ForceQuit key.Binding
+	Quit      key.Binding
+
+	// Navigation between the input, the list and the buttons
+	Next  key.Binding
+	Prev  key.Binding
+	Left  key.Binding
+	Right key.Binding
+	Up    key.Binding
+	Down  key.Binding
+
+	// Enter presses the focused button. Add, Start, Stop and Reset press a
+	// button directly and have no keys unless configured; Clear has c.
+	Select key.Binding
+	Add    key.Binding
+	Start  key.Binding
+	Stop   key.Binding
+	Reset  key.Binding
+	Clear  key.Binding
+	Undo   key.Binding
+
+	// Dismiss silences the oldest ringing alarm. Unbound means any key does.
+	Dismiss    key.Binding
+	DismissAll key.Binding
+	Snooze     key.Binding
+	Mute       key.Binding
+
+	VolumeUp   key.Binding
+	VolumeDown key.Binding
+
+	// On the selected timer
+	Toggle   key.Binding
+	Delete   key.Binding
+	ResetOne key.Binding
+	Finish   key.Binding
+	Edit     key.Binding
+	Cancel   key.Binding
+	Zoom     key.Binding
+
+	Pause       key.Binding
+	PauseAll    key.Binding
+	Copy        key.Binding
+	Restart     key.Binding
+	Recent      key.Binding
+	Preset      key.Binding
+	NewestFirst key.Binding
+	Sort        key.Binding
+	MoveUp      key.Binding
+	MoveDown    key.Binding
+	Group       key.Binding
+
+	ByGroup          key.Binding
+	CollapseGroup    key.Binding
+	ExpandGroups     key.Binding
+	CollapseRunning  key.Binding
+	CollapsePaused   key.Binding
+	CollapseFinished key.Binding
+
+	Words     key.Binding
+	Elapsed   key.Binding
+	Heatmap   key.Binding
+	Minimize  key.Binding
+	Compact   key.Binding
+	Reference key.Binding
+	Detail    key.Binding
+	Snapshot  key.Binding
+	Theme     key.Binding
+
+	Help key.Binding
+
+	// Answers to a y/n prompt
+	Yes key.Binding
+	No  key.Binding
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		ForceQuit: key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit now")),
+		Quit:      key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+
+		Next:  key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "next control")),
+		Prev:  key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "previous control")),
+		Left:  key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "previous button")),
+		Right: key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "next button")),
+		Up:    key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		Down:  key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+
+		Select: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "press focused button")),
+		Add:    key.NewBinding(key.WithHelp("", "add timer")),
+		Start:  key.NewBinding(key.WithHelp("", "resume all")),
+		Stop:   key.NewBinding(key.WithHelp("", "pause all")),
+		Reset:  key.NewBinding(key.WithHelp("", "clear all")),
+		Clear:  key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear finished")),
+		Undo:   key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "undo reset")),
+
+		Dismiss:    key.NewBinding(key.WithHelp("any key", "silence oldest alarm")),
+		DismissAll: key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "silence all alarms")),
+		Snooze:     key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "snooze")),
+		Mute:       key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "mute sound")),
+
+		VolumeUp:   key.NewBinding(key.WithKeys("+", "="), key.WithHelp("+", "volume up")),
+		VolumeDown: key.NewBinding(key.WithKeys("-"), key.WithHelp("-", "volume down")),
+
+		Toggle:   key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "pause/resume selected")),
+		Delete:   key.NewBinding(key.WithKeys("d", "x"), key.WithHelp("d/x", "delete selected")),
+		ResetOne: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "restart selected")),
+		Finish:   key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "finish selected")),
+		Edit:     key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit selected")),
+		Cancel:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel edit / leave zoom")),
+		Zoom:     key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "zoom selected")),
+
+		Pause:       key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause nearest")),
+		PauseAll:    key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "pause/resume all")),
+		Copy:        key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy time left")),
+		Restart:     key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "restart finished")),
+		Recent:      key.NewBinding(key.WithKeys("f1", "f2", "f3", "f4", "f5"), key.WithHelp("f1-f5", "recent duration")),
+		Preset:      key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "preset duration")),
+		NewestFirst: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "newest first")),
+		Sort:        key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "cycle sort order")),
+		MoveUp:      key.NewBinding(key.WithKeys("shift+up"), key.WithHelp("shift+↑", "move selected up")),
+		MoveDown:    key.NewBinding(key.WithKeys("shift+down"), key.WithHelp("shift+↓", "move selected down")),
+		Group:       key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "group by state")),
+
+		ByGroup:          key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "group by name")),
+		CollapseGroup:    key.NewBinding(key.WithKeys("alt+g"), key.WithHelp("alt+g", "collapse group")),
+		ExpandGroups:     key.NewBinding(key.WithKeys("alt+e"), key.WithHelp("alt+e", "expand groups")),
+		CollapseRunning:  key.NewBinding(key.WithKeys("alt+r"), key.WithHelp("alt+r", "collapse running")),
+		CollapsePaused:   key.NewBinding(key.WithKeys("alt+p"), key.WithHelp("alt+p", "collapse paused")),
+		CollapseFinished: key.NewBinding(key.WithKeys("alt+f"), key.WithHelp("alt+f", "collapse finished")),
+
+		Words:     key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "remaining in words")),
+		Elapsed:   key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "show elapsed")),
+		Heatmap:   key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "heatmap")),
+		Minimize:  key.NewBinding(key.WithKeys("M"), key.WithHelp("M", "minimize")),
+		Compact:   key.NewBinding(key.WithKeys("C"), key.WithHelp("C", "compact view")),
+		Reference: key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "mark T-0")),
+		Detail:    key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "detail pane")),
+		Snapshot:  key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "save snapshot")),
+		Theme:     key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "light/dark theme")),
+
+		Help: key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "toggle help")),
+
+		Yes: key.NewBinding(key.WithKeys("y", "Y", "enter"), key.WithHelp("y", "yes")),
+		No:  key.NewBinding(key.WithKeys("n", "N", "esc"), key.WithHelp("n", "no")),
+	}
+}
+
+// bindings names every binding as it is written in the config.
+func (k *keyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"force_quit":        &k.ForceQuit,
+		"quit":              &k.Quit,
+		"next":              &k.Next,
+		"prev":              &k.Prev,
+		"left":              &k.Left,
+		"right":             &k.Right,
+		"up":                &k.Up,
+		"down":              &k.Down,
+		"select":            &k.Select,
+		"add":               &k.Add,
+		"start":             &k.Start,
+		"stop":              &k.Stop,
+		"reset":             &k.Reset,
+		"clear":             &k.Clear,
+		"undo":              &k.Undo,
+		"dismiss":           &k.Dismiss,
+		"dismiss_all":       &k.DismissAll,
+		"snooze":            &k.Snooze,
+		"mute":              &k.Mute,
+		"volume_up":         &k.VolumeUp,
+		"volume_down":       &k.VolumeDown,
+		"toggle":            &k.Toggle,
+		"delete":            &k.Delete,
+		"reset_one":         &k.ResetOne,
+		"finish":            &k.Finish,
+		"edit":              &k.Edit,
+		"cancel":            &k.Cancel,
+		"zoom":              &k.Zoom,
+		"pause":             &k.Pause,
+		"pause_all":         &k.PauseAll,
+		"copy":              &k.Copy,
+		"restart":           &k.Restart,
+		"recent":            &k.Recent,
+		"preset":            &k.Preset,
+		"newest_first":      &k.NewestFirst,
+		"sort":              &k.Sort,
+		"move_up":           &k.MoveUp,
+		"move_down":         &k.MoveDown,
+		"group":             &k.Group,
+		"by_group":          &k.ByGroup,
+		"collapse_group":    &k.CollapseGroup,
+		"expand_groups":     &k.ExpandGroups,
+		"collapse_running":  &k.CollapseRunning,
+		"collapse_paused":   &k.CollapsePaused,
+		"collapse_finished": &k.CollapseFinished,
+		"words":             &k.Words,
+		"elapsed":           &k.Elapsed,
+		"heatmap":           &k.Heatmap,
+		"minimize":          &k.Minimize,
+		"compact":           &k.Compact,
+		"reference":         &k.Reference,
+		"detail":            &k.Detail,
+		"snapshot":          &k.Snapshot,
+		"theme":             &k.Theme,
+		"help":              &k.Help,
+		"yes":               &k.Yes,
+		"no":                &k.No,
+	}
+}
+
+// ShortHelp and FullHelp make keyMap a help.KeyMap.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Select, k.Quit, k.Help}
+}
+
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Next, k.Prev, k.Left, k.Right, k.Up, k.Down, k.Select, k.Help},
+		{
+			orButton(k.Add, "Add"), orButton(k.Start, "Start"), orButton(k.Stop, "Stop"), orButton(k.Reset, "Reset"), k.Undo, k.Clear,
+			k.Quit, k.ForceQuit, orHelp(k.Dismiss), k.DismissAll, k.Snooze, k.Mute, k.VolumeUp, k.VolumeDown,
+		},
+		{k.Toggle, k.Delete, k.ResetOne, k.Finish, k.Edit, k.Cancel, k.Zoom, k.Pause, k.PauseAll, k.Copy, k.Restart, k.Recent, k.Preset},
+		{k.NewestFirst, k.Sort, k.MoveUp, k.MoveDown, k.Group, k.CollapseRunning, k.CollapsePaused, k.CollapseFinished, k.ByGroup, k.CollapseGroup, k.ExpandGroups},
+		{k.Words, k.Elapsed, k.Heatmap, k.Minimize, k.Compact, k.Reference, k.Detail, k.Snapshot, k.Theme},
+	}
+}
+
+// orButton lists an unbound button action under its button instead, since
+// the help view skips bindings without keys.
+func orButton(b key.Binding, button string) key.Binding {
+	if len(b.Keys()) > 0 {
+		return b
+	}
+	return key.NewBinding(key.WithKeys(button), key.WithHelp("["+button+"]", b.Help().Desc))
+}
+
+// orHelp keeps an unbound binding in the help view under its help key.
+func orHelp(b key.Binding) key.Binding {
+	if len(b.Keys()) > 0 {
+		return b
+	}
+	return key.NewBinding(key.WithKeys(b.Help().Key), key.WithHelp(b.Help().Key, b.Help().Desc))
+}
+
+// newKeyMap applies the config's overrides to the defaults.
+func newKeyMap(overrides map[string][]string) (keyMap, error) {
+	k := defaultKeyMap()
+	named := k.bindings()
+	for name, keys := range overrides {
+		b, ok := named[name]
+		if !ok {
+			return k, fmt.Errorf("unknown key action %q", name)
+		}
+		b.SetKeys(keys...)
+		if len(keys) > 0 {
+			b.SetHelp(keys[0], b.Help().Desc)
+		}
+	}
+	return k, nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// uiStrings is the user-facing text of the view and its messages. A locale
+// file only needs the keys it translates; the rest stay English. Values with
+// %d, %s or %q are fmt layouts and must keep their verbs in order. Key
+// help, priority, sort and pomodoro phase names, and error details from
+// parsing or the system stay English.
+type uiStrings struct {
+	NewTimer   string `json:"new_timer"`
+	NoTimers   string `json:"no_timers"`
+	TimesUp    string `json:"times_up"`
+	Remaining  string `json:"remaining"`
+	Elapsed    string `json:"elapsed"`
+	Paused     string `json:"paused"`
+	Waiting    string `json:"waiting"`
+	Queued     string `json:"queued"`
+	Finishing  string `json:"finishing"`
+	Started    string `json:"started"`
+	Ends       string `json:"ends"`
+	Checkpoint string `json:"checkpoint"`
+	Done       string `json:"done"`
+	TimersDone string `json:"timers_done"`
+	Alarms     string `json:"alarms"`
+	Left       string `json:"left"`
+	Repeating  string `json:"repeating"`
+	Ungrouped  string `json:"ungrouped"`
+	Edit       string `json:"edit"`
+	TooSmall   string `json:"too_small"`
+
+	SectionRunning  string `json:"section_running"`
+	SectionPaused   string `json:"section_paused"`
+	SectionFinished string `json:"section_finished"`
+
+	// Remaining time in words (w)
+	UnderMinute  string `json:"under_minute"`
+	AboutMinute  string `json:"about_minute"`
+	AboutMinutes string `json:"about_minutes"`
+	AboutHour    string `json:"about_hour"`
+	AboutHours   string `json:"about_hours"`
+
+	// The totals line and the minimized summary
+	Totals         string `json:"totals"`
+	TotalsFinished string `json:"totals_finished"`
+	TotalsRinging  string `json:"totals_ringing"`
+	Summary        string `json:"summary"`
+	SummaryNext    string `json:"summary_next"`
+	SummaryDone    string `json:"summary_done"`
+
+	Muted      string `json:"muted"`
+	More       string `json:"more"`
+	ZoomBack   string `json:"zoom_back"`
+	NoFinishes string `json:"no_finishes"`
+
+	ConfirmQuit           string `json:"confirm_quit"`
+	ConfirmQuitUnfinished string `json:"confirm_quit_unfinished"`
+	ConfirmReset          string `json:"confirm_reset"`
+	ConfirmDuplicate      string `json:"confirm_duplicate"`
+
+	Detail detailStrings `json:"detail"`
+	Toast  toastStrings  `json:"toast"`
+	Add    string        `json:"add"`
+	Start  string        `json:"start"`
+	Stop   string        `json:"stop"`
+	Reset  string        `json:"reset"`
+	Clear  string        `json:"clear"`
+	Quit   string        `json:"quit"`
+	Help   string        `json:"help"`
+}
+
+var defaultStrings = uiStrings{
+	NewTimer:   "New Timer: ",
+	NoTimers:   "No timers running",
+	TimesUp:    "Time's Up!",
+	Remaining:  "remaining",
+	Elapsed:    "elapsed",
+	Paused:     "(Paused)",
+	Waiting:    "(Waiting)",
+	Queued:     "queued",
+	Finishing:  "finishing...",
+	Started:    "started",
+	Ends:       "ends",
+	Checkpoint: "paused at checkpoint",
+	Done:       "is done",
+	TimersDone: "timers done",
+	Alarms:     "alarms!",
+	Left:       "left",
+	Repeating:  "(repeating)",
+	Ungrouped:  "Ungrouped",
+	Edit:       "Edit",
+	TooSmall:   "Terminal too small; enlarge it for the full view",
+
+	SectionRunning:  "Running",
+	SectionPaused:   "Paused",
+	SectionFinished: "Finished",
+
+	UnderMinute:  "under a minute left",
+	AboutMinute:  "about a minute left",
+	AboutMinutes: "about %d minutes left",
+	AboutHour:    "about an hour left",
+	AboutHours:   "about %d hours left",
+
+	Totals:         "%d timers, %s total remaining",
+	TotalsFinished: " · %d finished",
+	TotalsRinging:  " (%d ringing)",
+	Summary:        "%d timers",
+	SummaryNext:    ", next in %s",
+	SummaryDone:    ", %d done",
+
+	Muted:      "[muted]",
+	More:       "%d more",
+	ZoomBack:   "z or esc to go back",
+	NoFinishes: "No timers have finished this session",
+
+	ConfirmQuit:           "Quit? (y/n)",
+	ConfirmQuitUnfinished: "Quit with %d unfinished timers? (y/n)",
+	ConfirmReset:          "Clear all %d timers? (y/n)",
+	ConfirmDuplicate:      "Similar timer #%d already running. Add anyway? (y/n)",
+
+	Detail: detailStrings{
+		ID:            "ID",
+		Label:         "Label",
+		Group:         "Group",
+		Priority:      "Priority",
+		Elapsed:       "Elapsed",
+		Duration:      "Duration",
+		Remaining:     "Remaining",
+		Status:        "Status",
+		Running:       "running",
+		Finished:      "finished",
+		Queued:        "queued",
+		Waiting:       "waiting",
+		Paused:        "paused",
+		Created:       "Created",
+		ThisRun:       "This run",
+		Finishes:      "Finishes",
+		Command:       "Command",
+		Checkpoint:    "Checkpoint",
+		Reached:       "(reached)",
+		Phase:         "Phase",
+		Repeat:        "Repeat",
+		Forever:       "forever",
+		More:          "%d more",
+		Sound:         "Sound",
+		SystemDefault: "system default",
+	},
+	Toast: toastStrings{
+		SoundMissing:     "Configured sound %s not found, using default",
+		SelectFirst:      "Select an unfinished timer in the list first",
+		CommandsDisabled: "On-finish commands are disabled; set allow_commands in the config",
+		TimerLimit:       "Timer limit reached (%d); raise max_timers to add more",
+		MoveNeedsOrder:   "Switch to creation order (o) to move timers",
+		NothingToUndo:    "Nothing to undo",
+		Restored:         "Restored %d timers",
+		Snoozed:          "Snoozed %s for %s",
+		Volume:           "Volume %d%%",
+		SessionFailed:    "Could not save session: %s",
+		SessionSaved:     "Saved session %s",
+		Paused:           "Paused %s",
+		Sorted:           "Sorted by %s",
+		CommandFailed:    "#%d command failed: %s",
+		WebhookFailed:    "#%d webhook failed: %s",
+		SoundFailed:      "Alarm sound failed (%s): %s",
+		AnnounceFailed:   "Announcement failed: %s",
+		CopyFailed:       "Could not copy: %s",
+		Copied:           "Copied %q",
+		RestoreFailed:    "Could not restore timers: %s",
+		FinishedClosed:   "%d timers finished while closed",
+	},
+	Add:   "Add",
+	Start: "Start",
+	Stop:  "Stop",
+	Reset: "Reset",
+	Clear: "Clear",
+	Quit:  "Quit",
+	Help:  "(Tab to navigate, Enter to select, ? for all keys)",
+}
+
+// detailStrings are the field names and values of the detail pane (i).
+type detailStrings struct {
+	ID            string `json:"id"`
+	Label         string `json:"label"`
+	Group         string `json:"group"`
+	Priority      string `json:"priority"`
+	Elapsed       string `json:"elapsed"`
+	Duration      string `json:"duration"`
+	Remaining     string `json:"remaining"`
+	Status        string `json:"status"`
+	Running       string `json:"running"`
+	Finished      string `json:"finished"`
+	Queued        string `json:"queued"`
+	Waiting       string `json:"waiting"`
+	Paused        string `json:"paused"`
+	Created       string `json:"created"`
+	ThisRun       string `json:"this_run"`
+	Finishes      string `json:"finishes"`
+	Command       string `json:"command"`
+	Checkpoint    string `json:"checkpoint"`
+	Reached       string `json:"reached"`
+	Phase         string `json:"phase"`
+	Repeat        string `json:"repeat"`
+	Forever       string `json:"forever"`
+	More          string `json:"more"`
+	Sound         string `json:"sound"`
+	SystemDefault string `json:"system_default"`
+}
+
+// toastStrings are the brief messages shown at the bottom of the screen.
+type toastStrings struct {
+	SoundMissing     string `json:"sound_missing"`
+	SelectFirst      string `json:"select_first"`
+	CommandsDisabled string `json:"commands_disabled"`
+	TimerLimit       string `json:"timer_limit"`
+	MoveNeedsOrder   string `json:"move_needs_order"`
+	NothingToUndo    string `json:"nothing_to_undo"`
+	Restored         string `json:"restored"`
+	Snoozed          string `json:"snoozed"`
+	Volume           string `json:"volume"`
+	SessionFailed    string `json:"session_failed"`
+	SessionSaved     string `json:"session_saved"`
+	Paused           string `json:"paused"`
+	Sorted           string `json:"sorted"`
+	CommandFailed    string `json:"command_failed"`
+	WebhookFailed    string `json:"webhook_failed"`
+	SoundFailed      string `json:"sound_failed"`
+	AnnounceFailed   string `json:"announce_failed"`
+	CopyFailed       string `json:"copy_failed"`
+	Copied           string `json:"copied"`
+	RestoreFailed    string `json:"restore_failed"`
+	FinishedClosed   string `json:"finished_closed"`
+}
+
+// loadLocale reads locales/<name>.json from the config directory on top of
+// the English defaults. An empty name or "en" uses the defaults as-is.
+func loadLocale(name string) (uiStrings, error) {
+	text := defaultStrings
+	if name == "" || name == "en" {
+		return text, nil
+	}
+	data, err := os.ReadFile(filepath.Join(configDir(), "locales", name+".json"))
+	if err != nil {
+		return text, err
+	}
+	if err := json.Unmarshal(data, &text); err != nil {
+		return text, err
+	}
+	return text, nil
+}
@@ -1,140 +1,1375 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
-// STYLES
-var (
-	focusedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-	blurredStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	cursorStyle  = focusedStyle.Copy()
-	noStyle      = lipgloss.NewStyle()
-	helpStyle    = blurredStyle.Copy()
+// maxRecent is how many distinct durations the quick bar remembers.
+const maxRecent = 5
 
-	focusedButton = focusedStyle.Copy().Render("[ %s ]")
-	blurredButton = fmt.Sprintf("[ %s ]", blurredStyle.Render("%s"))
+// presets are the durations 1 to 9 add, in key order.
+var presets = []time.Duration{
+	1 * time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+	15 * time.Minute,
+	20 * time.Minute,
+	30 * time.Minute,
+	45 * time.Minute,
+	time.Hour,
+}
+
+// Under finalCountdown remaining, timers show tenths of a second and the
+// clock ticks every fastTick.
+const (
+	finalCountdown = 10 * time.Second
+	fastTick       = 100 * time.Millisecond
+)
+
+// toastDuration is how long a transient notification stays on screen.
+const toastDuration = 4 * time.Second
+
+// alarmRepeat is how often the alarm sound replays while any timer is still
+// alarming, until a key press dismisses it.
+const alarmRepeat = 5 * time.Second
+
+// importantAlarmRepeat is the faster replay used while a high-priority
+// timer is among the alarming ones.
+const importantAlarmRepeat = 2 * time.Second
+
+type Focus int
+
+const (
+	INPUT = Focus(0)
+	LIST  = Focus(1)
+	ADD   = Focus(2)
+	START = Focus(3)
+	STOP  = Focus(4)
+	RESET = Focus(5)
+	CLEAR = Focus(6)
+	QUIT  = Focus(7)
+)
+
+type Priority int
+
+const (
+	LOW    = Priority(-1)
+	NORMAL = Priority(0)
+	HIGH   = Priority(1)
+)
+
+func (p Priority) String() string {
+	switch p {
+	case HIGH:
+		return "high"
+	case LOW:
+		return "low"
+	}
+	return "normal"
+}
+
+type Confirm int
+
+const (
+	CONFIRM_NONE      = Confirm(0)
+	CONFIRM_DUPLICATE = Confirm(1)
+	CONFIRM_QUIT      = Confirm(2)
+	CONFIRM_RESET     = Confirm(3)
+)
+
+type Section int
+
+const (
+	SECTION_RUNNING  = Section(0)
+	SECTION_PAUSED   = Section(1)
+	SECTION_FINISHED = Section(2)
+)
+
+type SortMode int
+
+const (
+	SORT_CREATED  = SortMode(0)
+	SORT_PRIORITY = SortMode(1)
+	SORT_SOONEST  = SortMode(2)
+	SORT_LATEST   = SortMode(3)
+)
+
+func (s SortMode) String() string {
+	switch s {
+	case SORT_PRIORITY:
+		return "priority"
+	case SORT_SOONEST:
+		return "soonest first"
+	case SORT_LATEST:
+		return "latest first"
+	}
+	return "creation order"
+}
+
+type Timer struct {
+	ID            int
+	Duration      time.Duration
+	Label         string
+	Remaining     time.Duration
+	Deadline      time.Time // When a running timer reaches zero; Remaining follows it
+	Running       bool
+	Finished      bool
+	Alarming      bool // Active alarm state (blinking/ringing)
+	Finishing     bool // Inside the configured grace period, alarm not fired yet
+	Priority      Priority
+	Queued        bool // Waiting for earlier steps of a sequence to finish
+	Waiting       bool // Held back by the max_running limit
+	CreatedAt     time.Time
+	PauseAt       time.Duration // Checkpoint: auto-pause when Remaining reaches it
+	CheckpointHit bool          // The checkpoint has already fired
+	Command       string        // Run through the shell on finish (allow_commands)
+	CountUp       bool          // Stopwatch: Elapsed counts up and never finishes by itself
+	Elapsed       time.Duration // Stopwatch time so far
+	StartedAt     time.Time     // Stopwatch: when Elapsed was zero, shifted on every resume
+	Pomodoro      bool          // Moves on to the next work or break phase when it finishes
+	Cycle         int           // Pomodoro: which work phase this is, from 1
+	OnBreak       bool          // Pomodoro: in a break after work phase Cycle
+	RunStart      time.Time     // When the current run (phase, repeat, restart or snooze) began; CreatedAt stays put
+	DismissedAt   time.Time     // When the alarm of a finished timer was dismissed
+	RangAt        time.Time     // When the alarm started ringing, to dismiss the oldest first
+	SoundFile     string        // Rings with this instead of the configured sound
+	Repeat        int           // Runs left after this one: 0 = none, -1 = forever
+	Group         string        // Named section in the by-group view; "" is ungrouped
+}
+
+// began is when t's current run started, as recorded in history: its
+// creation for the first run, otherwise the latest phase, repeat, restart
+// or snooze.
+func (t *Timer) began() time.Time {
+	if !t.RunStart.IsZero() {
+		return t.RunStart
+	}
+	return t.CreatedAt
+}
+
+// progress is how far through its duration t is, from 0 to 1.
+func (t *Timer) progress() float64 {
+	if t.Finished || t.Duration <= 0 {
+		return 1
+	}
+	return max(0, 1-float64(t.Remaining)/float64(t.Duration))
+}
+
+// pause stops t's countdown, keeping what is left of it.
+func (t *Timer) pause(now time.Time) {
+	if t.Running && t.CountUp {
+		t.Elapsed = now.Sub(t.StartedAt)
+	} else if t.Running {
+		t.Remaining = max(0, t.Deadline.Sub(now))
+	}
+	t.Running = false
+}
+
+type model struct {
+	textInput   textinput.Model
+	overallBar  progress.Model // Aggregate completion of every timer
+	timerBar    progress.Model // Shared by every row; only ever rendered with ViewAs
+	timers      []*Timer
+	nextID      int // Lowest ID the next timer may take; see newID
+	blink       bool
+	width       int
+	height      int
+	focusIndex  Focus
+	focusState  Focus
+	selectedID  int                // ID of the highlighted timer while focus is on LIST
+	alarmCancel context.CancelFunc // To stop the playing sound
+	newestFirst bool               // Render the list newest-first without reordering m.timers
+	sortMode    SortMode
+	repeating   bool        // An alarm repeat loop is scheduled
+	seqHead     *Timer      // Running step of the active sequence
+	sequence    []*Timer    // Queued steps, started one at a time as seqHead finishes
+	lastSound   soundResult // How the most recent alarm sound resolved
+	toast       string      // Transient notification shown under the buttons
+	toastUntil  time.Time
+	recent      []time.Duration // Distinct durations used lately, newest first
+	recallIndex int             // Entry of recent shown in the input by up/down, or -1
+
+	confirming      Confirm // Pending y/n question, answered before any other key
+	confirmPrompt   string
+	pendingSpec     timerSpec // Timer waiting on CONFIRM_DUPLICATE
+	cfg             config
+	text            uiStrings
+	styles          styles
+	inline          bool        // Running without the alt screen; render compact and left-aligned
+	showDetail      bool        // Show the detail pane for the selected timer
+	minimized       bool        // List collapsed into a summary line
+	finishTimes     []time.Time // When each timer finished this session, for the heatmap
+	showHeatmap     bool
+	showWords       bool      // Remaining time as "about 5 minutes left"
+	showElapsed     bool      // Elapsed time before the remaining time (L)
+	compact         bool      // Everything on a few lines, no buttons (--compact or C)
+	muted           bool      // Alarms blink but play no sound
+	inputError      string    // Why the last submitted input was rejected
+	editingID       int       // Timer whose time the input is editing (e), or 0
+	zoomID          int       // Timer shown full screen in big digits (z), or 0
+	session         string    // Identifies this run in the history log
+	volume          int       // Alarm volume in percent, changed with +/-
+	undoBuffer      undoState // What the last reset removed, for u
+	theme           string    // Name of the active theme, switched with t
+	soundFile       string    // Preferred alarm sound, from TUI_TIMER_SOUND or sound_file
+	keys            keyMap
+	help            help.Model       // Full key list, toggled with ?
+	grouped         bool             // List split into Running / Paused / Finished
+	collapsed       [3]bool          // Per Section, while grouped
+	byGroup         bool             // List split into the timers' named groups
+	collapsedGroups map[string]bool  // Per group name, while byGroup
+	referenceTime   time.Time        // T-0 marker; zero when unset
+	nowFunc         func() time.Time // The clock, time.Now outside tests
+}
+
+func initialModel(cfg config, text uiStrings, saved savedState, initial []time.Duration) model {
+	ti := textinput.New()
+	ti.Placeholder = "10s (e.g. 5m, 1h30m)"
+	ti.Focus()
+	ti.CharLimit = 80
+	ti.Width = 30
+
+	themeName := cfg.Theme
+	if themeName == "" {
+		themeName = defaultTheme()
+	}
+	st := newStyles(themes[themeName], cfg.Styles)
+	ti.PromptStyle = st.Input
+	ti.TextStyle = st.Input
+
+	m := model{
+		textInput:   ti,
+		overallBar:  progress.New(progress.WithDefaultGradient(), progress.WithWidth(40)),
+		timerBar:    progress.New(progress.WithDefaultGradient(), progress.WithWidth(20), progress.WithoutPercentage()),
+		focusIndex:  INPUT,
+		timers:      []*Timer{},
+		nextID:      1,
+		recallIndex: -1,
+		cfg:         cfg,
+		text:        text,
+		styles:      st,
+		theme:       themeName,
+		showWords:   cfg.RemainingWords,
+		soundFile:   configuredSound(cfg.SoundFile),
+		help:        help.New(),
+		volume:      cfg.volume(),
+		nowFunc:     time.Now,
+	}
+	m.session = newSessionID(m.nowFunc())
+	m.keys, _ = newKeyMap(cfg.Keys) // Already checked by loadConfig
+	m.recent = saved.Recent[:min(len(saved.Recent), maxRecent)]
+	if len(saved.Timers) > 0 {
+		m.restoreState(saved, m.nowFunc())
+	}
+	for _, d := range initial {
+		m.addTimer(timerSpec{Duration: d})
+	}
+	if soundMissing(m.soundFile) {
+		m.setToast(fmt.Sprintf(m.text.Toast.SoundMissing, m.soundFile))
+	}
+	return m
+}
+
+func (m model) Init() tea.Cmd {
+	return tea.Batch(
+		textinput.Blink,
+		tickCmd(),
+		m.blinkCmd(),
+	)
+}
+
+type tickMsg time.Time
+type blinkMsg time.Time
+type alarmRepeatMsg time.Time
+
+// dismissAlarmMsg silences alarms from outside the terminal (SIGUSR1).
+type dismissAlarmMsg struct{}
+
+func tickCmd() tea.Cmd {
+	return tickEvery(time.Second)
+}
+
+func tickEvery(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// nextTick ticks every fastTick while any running countdown is in its
+// final seconds, so they can show tenths.
+func (m model) nextTick() tea.Cmd {
+	for _, t := range m.timers {
+		if t.Running && !t.CountUp && t.Remaining <= finalCountdown {
+			return tickEvery(fastTick)
+		}
+	}
+	return tickCmd()
+}
+
+// blinkCmd schedules the next alarm blink, or nothing when blinking is
+// turned off in the config.
+func (m model) blinkCmd() tea.Cmd {
+	if m.cfg.noBlink() {
+		return nil
+	}
+	return tea.Tick(m.cfg.blinkInterval(), func(t time.Time) tea.Msg {
+		return blinkMsg(t)
+	})
+}
+
+// alarmRepeatCmd schedules the next alarm replay, sooner when a
+// high-priority alarm is ringing.
+func (m model) alarmRepeatCmd() tea.Cmd {
+	interval := alarmRepeat
+	if m.importantAlarming() {
+		interval = importantAlarmRepeat
+	}
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return alarmRepeatMsg(t)
+	})
+}
+
+func (m model) GetNewID() int {
+	return newID(m.timers, m.nextID)
+}
+
+// newID is the ID for the next timer: next, or one past the highest ID in
+// use if that is larger. IDs are never reused within a session.
+func newID(timers []*Timer, next int) int {
+	id := max(next, 1)
+	for _, t := range timers {
+		id = max(id, t.ID+1)
+	}
+	return id
+}
+
+// tick brings every running timer up to now. It returns the countdowns
+// that reached zero and the ones that paused at their checkpoint. A
+// deadline passed while the machine slept finishes on the first tick after.
+func tick(timers []*Timer, now time.Time, grace time.Duration) (finished, checkpoints []*Timer) {
+	// Compare wall clocks: the monotonic clock stops during suspend, which
+	// would leave every deadline as far off as when the machine slept
+	now = now.Round(0)
+	for _, t := range timers {
+		if t.Running && t.CountUp {
+			t.Elapsed = now.Sub(t.StartedAt)
+		} else if t.Running && t.Remaining > 0 {
+			// Count from the deadline, so slow ticks never drift
+			t.Remaining = max(0, t.Deadline.Sub(now))
+			t.Finishing = t.Remaining > 0 && t.Remaining <= grace
+			if t.Remaining <= 0 {
+				finished = append(finished, t)
+			} else if t.PauseAt > 0 && !t.CheckpointHit && t.Remaining <= t.PauseAt {
+				t.Running = false
+				t.CheckpointHit = true
+				checkpoints = append(checkpoints, t)
+			}
+		}
+	}
+	return finished, checkpoints
+}
+
+// displayTimers returns the timers in the order they are rendered. The
+// underlying slice always stays in creation order.
+func (m model) displayTimers() []*Timer {
+	ordered := slices.Clone(m.timers)
+	if m.newestFirst {
+		slices.Reverse(ordered)
+	}
+	switch m.sortMode {
+	case SORT_PRIORITY:
+		slices.SortStableFunc(ordered, func(a, b *Timer) int {
+			return int(b.Priority - a.Priority)
+		})
+	case SORT_SOONEST, SORT_LATEST:
+		// Stopwatches never finish, so they go last either way
+		slices.SortStableFunc(ordered, func(a, b *Timer) int {
+			if a.CountUp != b.CountUp {
+				if a.CountUp {
+					return 1
+				}
+				return -1
+			}
+			if m.sortMode == SORT_LATEST {
+				a, b = b, a
+			}
+			return cmp.Compare(a.Remaining, b.Remaining)
+		})
+	}
+	if m.grouped {
+		ordered = slices.DeleteFunc(ordered, func(t *Timer) bool { return m.collapsed[t.section()] })
+		slices.SortStableFunc(ordered, func(a, b *Timer) int {
+			return int(a.section() - b.section())
+		})
+	}
+	if m.byGroup {
+		groups := m.groupNames()
+		ordered = slices.DeleteFunc(ordered, func(t *Timer) bool { return m.collapsedGroups[t.Group] })
+		slices.SortStableFunc(ordered, func(a, b *Timer) int {
+			return slices.Index(groups, a.Group) - slices.Index(groups, b.Group)
+		})
+	}
+	return ordered
+}
+
+// groupNames lists the timers' groups in the order they were first used,
+// with the ungrouped timers last.
+func (m model) groupNames() []string {
+	var names []string
+	ungrouped := false
+	for _, t := range m.timers {
+		if t.Group == "" {
+			ungrouped = true
+		} else if !slices.Contains(names, t.Group) {
+			names = append(names, t.Group)
+		}
+	}
+	if ungrouped {
+		names = append(names, "")
+	}
+	return names
+}
+
+// toggleGroup collapses or expands the named group, like toggleSection.
+func (m *model) toggleGroup(name string) {
+	if m.collapsedGroups == nil {
+		m.collapsedGroups = make(map[string]bool)
+	}
+	m.collapsedGroups[name] = !m.collapsedGroups[name]
+	m.selectShown()
+}
+
+// section is the group t is listed under in the grouped view. Waiting and
+// queued timers are not counting down, so they count as paused.
+func (t *Timer) section() Section {
+	switch {
+	case t.Finished:
+		return SECTION_FINISHED
+	case t.Running:
+		return SECTION_RUNNING
+	}
+	return SECTION_PAUSED
+}
+
+// toggleSection collapses or expands s, moving the selection off any row
+// that was just hidden.
+func (m *model) toggleSection(s Section) {
+	m.collapsed[s] = !m.collapsed[s]
+	m.selectShown()
+}
+
+// selectShown moves the selection off a row that is no longer shown, or
+// out of the list when nothing is.
+func (m *model) selectShown() {
+	if m.focusIndex != LIST {
+		return
+	}
+	shown := m.displayTimers()
+	if len(shown) == 0 {
+		m.leaveList()
+	} else if !slices.ContainsFunc(shown, func(t *Timer) bool { return t.ID == m.selectedID }) {
+		m.selectedID = shown[0].ID
+	}
+}
+
+// submitInput creates a running timer from the text input, or runs one of
+// the input commands. Invalid input is left in place for the user to fix,
+// with the reason shown under the field.
+func (m *model) submitInput() {
+	value := m.textInput.Value()
+	m.inputError = ""
+	if m.editingID != 0 {
+		m.submitEdit(value)
+		return
+	}
+	if d, ok, err := parseSetInput(value); ok {
+		if err != nil {
+			m.setInputError(err)
+			return
+		}
+		t := m.selectedTimer()
+		if t == nil || t.Finished || t.CountUp {
+			m.setToast(m.text.Toast.SelectFirst)
+			return
+		}
+		m.setRemaining(t, d)
+		m.textInput.SetValue("")
+		return
+	}
+	if specs, ok, err := parseSequenceInput(value); ok {
+		if err != nil {
+			m.setInputError(err)
+			return
+		}
+		m.addSequence(specs)
+		m.textInput.SetValue("")
+		return
+	}
+
+	spec, err := parseTimerInput(value)
+	if err != nil {
+		m.setInputError(err)
+		return
+	}
+	if spec.Command != "" && !m.cfg.commandsAllowed() {
+		m.setToast(m.text.Toast.CommandsDisabled)
+		return
+	}
+	if m.cfg.WarnDuplicates {
+		if dup := m.findSimilar(spec); dup != nil {
+			m.confirming = CONFIRM_DUPLICATE
+			m.confirmPrompt = fmt.Sprintf(m.text.ConfirmDuplicate, dup.ID)
+			m.pendingSpec = spec
+			return
+		}
+	}
+	if m.addTimer(spec) != nil {
+		m.textInput.SetValue("")
+	}
+}
+
+// restartTimer re-arms a timer to its full duration and starts it.
+func (m *model) restartTimer(t *Timer) {
+	t.Remaining = t.Duration
+	t.Finished = false
+	t.Alarming = false
+	t.Finishing = false
+	t.CheckpointHit = false
+	t.RunStart = m.nowFunc()
+	m.startTimer(t)
+}
+
+// editSelected loads the selected timer's remaining time into the input for
+// editing; submitting it changes the timer instead of adding one.
+func (m *model) editSelected() tea.Cmd {
+	t := m.selectedTimer()
+	if t == nil || t.Finished || t.Queued || t.CountUp {
+		return nil
+	}
+	m.editingID = t.ID
+	m.inputError = ""
+	m.textInput.SetValue(t.Remaining.Round(time.Second).String())
+	m.textInput.CursorEnd()
+	m.focusIndex = INPUT
+	return m.textInput.Focus()
+}
+
+// submitEdit gives the timer being edited the new duration, which a later
+// restart also uses.
+func (m *model) submitEdit(value string) {
+	d, err := parseTimerDuration(strings.TrimSpace(value))
+	if err == nil && d <= 0 {
+		err = errors.New("duration must be positive")
+	}
+	if err != nil {
+		m.setInputError(err)
+		return
+	}
+	for _, t := range m.timers {
+		if t.ID == m.editingID && !t.Finished {
+			t.Duration = d
+			if t.PauseAt >= d {
+				t.PauseAt = 0 // The checkpoint is no longer inside the timer
+			}
+			m.setRemaining(t, d)
+		}
+	}
+	m.cancelEdit()
+}
+
+// cancelEdit leaves edit mode with an empty input.
+func (m *model) cancelEdit() {
+	m.editingID = 0
+	m.inputError = ""
+	m.textInput.SetValue("")
+}
+
+// setRemaining sets t's remaining time exactly, clamped to its duration.
+func (m *model) setRemaining(t *Timer, d time.Duration) {
+	t.Remaining = min(d, t.Duration)
+	t.Deadline = m.nowFunc().Add(t.Remaining)
+	t.Finishing = t.Remaining <= time.Duration(m.cfg.GracePeriod)
+	if t.PauseAt > 0 && t.Remaining > t.PauseAt {
+		t.CheckpointHit = false
+	}
+}
+
+// findSimilar returns an unfinished timer with the same duration and label.
+func (m model) findSimilar(spec timerSpec) *Timer {
+	for _, t := range m.timers {
+		label := t.Label
+		if label == m.templateLabel(t.ID) {
+			label = "" // Named by label_template, so it was typed unlabeled
+		}
+		if !t.Finished && t.Duration == spec.Duration && label == spec.Label {
+			return t
+		}
+	}
+	return nil
+}
+
+// templateLabel is the label_template name for timer id, or "" without one.
+func (m model) templateLabel(id int) string {
+	if m.cfg.LabelTemplate == "" {
+		return ""
+	}
+	return strings.ReplaceAll(m.cfg.LabelTemplate, "{n}", strconv.Itoa(id))
+}
+
+// requestQuit exits, asking first while any timer is unfinished, or always
+// when ask is set.
+func (m model) requestQuit(ask bool) (model, tea.Cmd) {
+	unfinished := 0
+	for _, t := range m.timers {
+		if !t.Finished {
+			unfinished++
+		}
+	}
+	if unfinished == 0 && !ask {
+		if m.alarmCancel != nil {
+			m.alarmCancel()
+		}
+		return m, tea.Quit
+	}
+	m.confirming = CONFIRM_QUIT
+	m.confirmPrompt = m.text.ConfirmQuit
+	if unfinished > 0 {
+		m.confirmPrompt = fmt.Sprintf(m.text.ConfirmQuitUnfinished, unfinished)
+	}
+	return m, nil
+}
+
+// answerConfirm carries out the action waiting on a "y" answer.
+func (m model) answerConfirm() (model, tea.Cmd) {
+	kind := m.confirming
+	m.confirming = CONFIRM_NONE
+	m.confirmPrompt = ""
+
+	switch kind {
+	case CONFIRM_DUPLICATE:
+		if m.addTimer(m.pendingSpec) != nil {
+			m.textInput.SetValue("")
+		}
+	case CONFIRM_QUIT:
+		if m.alarmCancel != nil {
+			m.alarmCancel()
+		}
+		return m, tea.Quit
+	case CONFIRM_RESET:
+		m.reset()
+	}
+	return m, nil
+}
+
+// addTimer creates a timer from spec and starts it. At the max_timers cap
+// it only says so and returns nil.
+func (m *model) addTimer(spec timerSpec) *Timer {
+	if limit := m.cfg.maxTimers(); limit > 0 && len(m.timers) >= limit {
+		m.setToast(fmt.Sprintf(m.text.Toast.TimerLimit, limit))
+		return nil
+	}
+	id := m.GetNewID()
+	m.nextID = id + 1
+	m.undoBuffer = undoState{} // A new timer ends the chance to undo a reset
+	label := spec.Label
+	if label == "" && spec.Command != "" {
+		label = commandLabel(spec.Command)
+	}
+	if label == "" {
+		label = m.templateLabel(id)
+	}
+	t := &Timer{
+		ID:        id,
+		Label:     label,
+		Duration:  spec.Duration,
+		Remaining: spec.Duration,
+		Running:   false,
+		Finished:  false,
+		Alarming:  false,
+		Priority:  spec.Priority,
+		CreatedAt: m.nowFunc(),
+		PauseAt:   spec.PauseAt,
+		Command:   spec.Command,
+		CountUp:   spec.CountUp,
+		SoundFile: spec.SoundFile,
+		Repeat:    spec.Repeat,
+		Group:     spec.Group,
+	}
+	if spec.Pomodoro {
+		t.Pomodoro = true
+		t.Cycle = 1
+		t.Label = pomodoroPhase(t)
+	}
+	m.timers = append(m.timers, t)
+	m.startTimer(t)
+	if !t.CountUp && !t.Pomodoro {
+		m.rememberDuration(spec.Duration)
+	}
+	return t
+}
+
+// recall steps through the recent durations in the input like shell
+// history: older on up, newer on down, and back to an empty field past the
+// newest. It never replaces typed text, and reports false when it did
+// nothing so down can still leave the input.
+func (m *model) recall(older bool) bool {
+	value := m.textInput.Value()
+	if m.recallIndex >= len(m.recent) || m.recallIndex >= 0 && value != m.recent[m.recallIndex].String() {
+		m.recallIndex = -1 // Edited since it was recalled
+	}
+	if m.recallIndex < 0 && (value != "" || !older) {
+		return false
+	}
+
+	if older {
+		if m.recallIndex+1 >= len(m.recent) {
+			return len(m.recent) > 0
+		}
+		m.recallIndex++
+	} else {
+		m.recallIndex--
+	}
+	if m.recallIndex < 0 {
+		m.textInput.SetValue("")
+	} else {
+		m.textInput.SetValue(m.recent[m.recallIndex].String())
+		m.textInput.CursorEnd()
+	}
+	return true
+}
+
+// rememberDuration moves d to the front of the recent list.
+func (m *model) rememberDuration(d time.Duration) {
+	m.recent = slices.DeleteFunc(m.recent, func(r time.Duration) bool { return r == d })
+	m.recent = slices.Insert(m.recent, 0, d)
+	if len(m.recent) > maxRecent {
+		m.recent = m.recent[:maxRecent]
+	}
+}
+
+// startTimer sets t running, or waiting if max_running timers already are.
+func (m *model) startTimer(t *Timer) {
+	if m.cfg.MaxRunning > 0 && m.runningCount() >= m.cfg.MaxRunning {
+		t.Running = false
+		t.Waiting = true
+		return
+	}
+	t.Running = true
+	t.Waiting = false
+	now := m.nowFunc()
+	t.Deadline = now.Add(t.Remaining)
+	t.StartedAt = now.Add(-t.Elapsed)
+}
+
+func (m model) runningCount() int {
+	n := 0
+	for _, t := range m.timers {
+		if t.Running {
+			n++
+		}
+	}
+	return n
+}
+
+// startWaiting starts waiting timers, oldest first, while there is room
+// under max_running.
+func (m *model) startWaiting() {
+	for _, t := range m.timers {
+		if t.Waiting && !t.Running && !t.Finished {
+			m.startTimer(t)
+			if t.Waiting {
+				return
+			}
+		}
+	}
+}
+
+// addSequence queues timers that run strictly one after another. If a
+// sequence is already running the new steps join the end of its queue.
+func (m *model) addSequence(specs []timerSpec) {
+	for _, spec := range specs {
+		t := m.addTimer(spec)
+		if t == nil {
+			break
+		}
+		t.Running = false
+		t.Waiting = false
+		t.Queued = true
+		m.sequence = append(m.sequence, t)
+	}
+	if m.seqHead == nil {
+		m.advanceSequence()
+	}
+}
+
+// advanceSequence starts the next queued step, if any.
+func (m *model) advanceSequence() {
+	m.seqHead = nil
+	if len(m.sequence) == 0 {
+		return
+	}
+	next := m.sequence[0]
+	m.sequence = m.sequence[1:]
+	next.Queued = false
+	m.startTimer(next)
+	m.seqHead = next
+}
+
+// setInputError shows err under the input until the field is edited.
+func (m *model) setInputError(err error) {
+	m.inputError = strings.TrimPrefix(err.Error(), "time: ")
+}
+
+func (m *model) setToast(msg string) {
+	m.toast = msg
+	m.toastUntil = m.nowFunc().Add(toastDuration)
+}
+
+// finish moves timers into the finished, alarming state, advances the
+// sequence and starts the alarm.
+func (m *model) finish(timers []*Timer, now time.Time) tea.Cmd {
+	if len(timers) == 0 {
+		return nil
+	}
+
+	var entries []historyEntry
+	for _, t := range timers {
+		m.finishTimes = append(m.finishTimes, now)
+		t.Running = false
+		t.Waiting = false // Its max_running slot is not needed any more
+		t.Queued = false
+		m.sequence = slices.DeleteFunc(m.sequence, func(o *Timer) bool { return o == t })
+		t.Remaining = 0
+		t.Finishing = false
+		t.Finished = true
+		t.Alarming = true
+		t.RangAt = now
+		t.DismissedAt = time.Time{}
+		entries = append(entries, newHistoryEntry(t, now, m.session))
+	}
+	if m.seqHead != nil && m.seqHead.Finished {
+		m.advanceSequence()
+	}
+	m.startWaiting()
+
+	cmds := []tea.Cmd{m.startAlarm(timers, false), recordHistory(entries)}
+	for _, t := range timers {
+		if t.Command != "" && m.cfg.commandsAllowed() {
+			cmds = append(cmds, runFinishCommand(t.ID, t.Command))
+		}
+		if m.cfg.WebhookURL != "" && !m.cfg.SafeMode {
+			cmds = append(cmds, postWebhook(m.cfg.WebhookURL, t, now))
+		}
+	}
+	if !m.repeating {
+		m.repeating = true
+		cmds = append(cmds, m.alarmRepeatCmd())
+	}
+	// Pomodoros and repeating timers go straight on once their alarm and
+	// history are set up. A repeat chimes once rather than ringing on.
+	for _, t := range timers {
+		if t.Pomodoro {
+			m.nextPhase(t, now)
+		} else if t.Repeat != 0 {
+			if t.Repeat > 0 {
+				t.Repeat--
+			}
+			m.restartTimer(t)
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// listVisible reports whether the timer list is on screen and focusable.
+func (m model) listVisible() bool {
+	return len(m.displayTimers()) > 0 && !m.minimized && !m.showHeatmap
+}
+
+// summary is the one-line overview used when the list is minimized.
+func (m model) summary() string {
+	done := 0
+	for _, t := range m.timers {
+		if t.Finished {
+			done++
+		}
+	}
+
+	s := fmt.Sprintf(m.text.Summary, len(m.timers))
+	if next := m.nearestRunning(); next != nil {
+		s += fmt.Sprintf(m.text.SummaryNext, next.Remaining.Round(time.Second))
+	}
+	if done > 0 {
+		s += fmt.Sprintf(m.text.SummaryDone, done)
+	}
+	return s
+}
+
+// selectedTimer returns the highlighted timer, or nil if it no longer exists.
+func (m model) selectedTimer() *Timer {
+	for _, t := range m.timers {
+		if t.ID == m.selectedID {
+			return t
+		}
+	}
+	return nil
+}
+
+// moveSelected swaps the selected timer with its neighbor delta rows away.
+// Sorted views decide their own order, so it only works in creation order.
+func (m *model) moveSelected(delta int) {
+	if m.sortMode != SORT_CREATED {
+		m.setToast(m.text.Toast.MoveNeedsOrder)
+		return
+	}
+	shown := m.displayTimers()
+	i := slices.Index(shown, m.selectedTimer())
+	if i < 0 || i+delta < 0 || i+delta >= len(shown) {
+		return
+	}
+	a := slices.Index(m.timers, shown[i])
+	b := slices.Index(m.timers, shown[i+delta])
+	m.timers[a], m.timers[b] = m.timers[b], m.timers[a]
+}
+
+// moveSelection moves the highlight by delta rows in display order. It
+// reports false when the move would leave the list.
+func (m *model) moveSelection(delta int) bool {
+	shown := m.displayTimers()
+	i := slices.IndexFunc(shown, func(t *Timer) bool { return t.ID == m.selectedID })
+	if i+delta < 0 || i+delta >= len(shown) {
+		return false
+	}
+	m.selectedID = shown[i+delta].ID
+	return true
+}
+
+// startAlarm stops any sound still playing and starts a new one for the
+// given timers, followed by the spoken announcement if one is configured.
+// A replay only repeats the sound: the announcement and the "count" toast
+// happen once, when the timers finish.
+func (m *model) startAlarm(timers []*Timer, replay bool) tea.Cmd {
+	if m.alarmCancel != nil {
+		m.alarmCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.alarmCancel = cancel
+
+	// Sounds play one after another, never overlapping. Timers sharing a
+	// sound share a play unless batch_sound is "sequential".
+	var plays []alarmPlay
+	if m.cfg.BatchSound == "sequential" {
+		for _, t := range timers {
+			plays = append(plays, alarmPlay{m.timerSound(t), fmt.Sprintf("%s %s", m.timerName(t), m.text.Done)})
+		}
+	} else {
+		var sounds []string
+		names := map[string][]string{}
+		for _, t := range timers {
+			sf := m.timerSound(t)
+			if _, ok := names[sf]; !ok {
+				sounds = append(sounds, sf)
+			}
+			names[sf] = append(names[sf], m.timerName(t))
+		}
+		for _, sf := range sounds {
+			plays = append(plays, alarmPlay{sf, fmt.Sprintf("%s %s", strings.Join(names[sf], ", "), m.text.Done)})
+		}
+		if m.cfg.BatchSound == "count" && len(timers) > 1 {
+			phrase := fmt.Sprintf("%d %s", len(timers), m.text.TimersDone)
+			if !replay {
+				m.setToast(phrase)
+			}
+			for i := range plays {
+				plays[i].phrase = ""
+			}
+			plays[0].phrase = phrase
+		}
+	}
+
+	if replay {
+		for i := range plays {
+			plays[i].phrase = ""
+		}
+	}
+	if m.muted {
+		return nil // Blink only
+	}
+	announceCmd, volume := m.cfg.AnnounceCommand, m.volume
+	return func() tea.Msg {
+		var res soundResult
+		for _, play := range plays {
+			res = playSound(ctx, play.sound, volume)
+			if announceCmd != "" && play.phrase != "" && ctx.Err() == nil {
+				res.AnnounceErr = announce(ctx, announceCmd, play.phrase)
+			}
+			if ctx.Err() != nil || res.Err != nil {
+				break
+			}
+		}
+		return soundResultMsg(res)
+	}
+}
+
+// copyText is what y puts on the clipboard, e.g. "Pasta: 12m0s left".
+func (m model) copyText(t *Timer) string {
+	switch {
+	case t.Finished:
+		return fmt.Sprintf("%s: %s", m.timerName(t), m.text.TimesUp)
+	case t.CountUp:
+		return fmt.Sprintf("%s: %s elapsed", m.timerName(t), t.Elapsed.Round(time.Second))
+	}
+	return fmt.Sprintf("%s: %s left", m.timerName(t), t.Remaining.Round(time.Second))
+}
+
+// alarmPlay is one sound in an alarm, followed by its announcement.
+type alarmPlay struct {
+	sound  string
+	phrase string
+}
+
+// timerSound is the sound t rings with: its own, or the configured one.
+func (m model) timerSound(t *Timer) string {
+	if t.SoundFile != "" {
+		return t.SoundFile
+	}
+	return m.soundFile
+}
+
+// timerName is how a timer is referred to in announcements.
+func (m model) timerName(t *Timer) string {
+	if t.Label != "" {
+		return t.Label
+	}
+	return fmt.Sprintf("Timer %d", t.ID)
+}
+
+// dismissAlarms clears every alarming timer and stops the sound. It reports
+// whether anything was alarming.
+func (m *model) dismissAlarms() bool {
+	anyAlarming := false
+	for _, t := range m.timers {
+		if t.Alarming {
+			t.Alarming = false
+			anyAlarming = true
+			if t.Finished {
+				t.DismissedAt = m.nowFunc()
+			}
+		}
+	}
+
+	if m.alarmCancel != nil {
+		m.alarmCancel() // Kill the sound process
+		m.alarmCancel = nil
+	}
+	return anyAlarming
+}
+
+// dismissOne clears the oldest alarming timer. The sound carries on while
+// others still ring. It reports whether anything was alarming.
+func (m *model) dismissOne() bool {
+	t := m.oldestAlarming()
+	if t == nil {
+		return false
+	}
+	t.Alarming = false
+	if t.Finished {
+		t.DismissedAt = m.nowFunc()
+	}
+	if !m.anyAlarming() && m.alarmCancel != nil {
+		m.alarmCancel()
+		m.alarmCancel = nil
+	}
+	return true
+}
+
+// oldestAlarming is the timer that has been ringing longest, or nil.
+func (m model) oldestAlarming() *Timer {
+	var oldest *Timer
+	for _, t := range m.timers {
+		if t.Alarming && (oldest == nil || t.RangAt.Before(oldest.RangAt)) {
+			oldest = t
+		}
+	}
+	return oldest
+}
+
+// navigates reports whether msg moves the focus or the highlight.
+func (m model) navigates(msg tea.KeyMsg) bool {
+	if m.focusIndex == INPUT && msg.Type == tea.KeyRunes {
+		return false // hjkl are letters while typing
+	}
+	return key.Matches(msg, m.keys.Next, m.keys.Prev, m.keys.Left, m.keys.Right, m.keys.Up, m.keys.Down)
+}
+
+// alarmCount is how many timers are ringing.
+func (m model) alarmCount() int {
+	n := 0
+	for _, t := range m.timers {
+		if t.Alarming {
+			n++
+		}
+	}
+	return n
+}
+
+// removeDismissed drops finished timers whose alarm was dismissed at least
+// remove_finished ago.
+func (m *model) removeDismissed(now time.Time) {
+	delay := time.Duration(m.cfg.RemoveFinished)
+	if delay <= 0 {
+		return
+	}
+	m.timers = slices.DeleteFunc(m.timers, func(t *Timer) bool {
+		return t.Finished && !t.Alarming && !t.DismissedAt.IsZero() && now.Sub(t.DismissedAt) >= delay
+	})
+	m.keepSelection()
+}
 
-	// Animation styles
-	alarmStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true) // Red bold
-)
+// reset removes every timer and stops the sound. The timers are kept, with
+// their time frozen, until u brings them back or a new timer is added.
+func (m *model) reset() {
+	if m.alarmCancel != nil {
+		m.alarmCancel()
+		m.alarmCancel = nil
+	}
+	now := m.nowFunc()
+	for _, t := range m.timers {
+		if t.Running {
+			t.pause(now)
+			t.Running = true // Resumed from the frozen time by undoReset
+		}
+		t.Alarming = false
+	}
+	m.undoBuffer = undoState{timers: m.timers, seqHead: m.seqHead, sequence: m.sequence}
+	m.timers = []*Timer{}
+	m.seqHead = nil
+	m.sequence = nil
+	m.keepSelection()
+}
 
-type Focus int
+// undoState is everything a reset clears, kept so u can put it back.
+type undoState struct {
+	timers   []*Timer
+	seqHead  *Timer   // The sequence step that was running
+	sequence []*Timer // Steps still queued behind it
+}
 
-const (
-	INPUT = Focus(0)
-	ADD   = Focus(1)
-	START = Focus(2)
-	STOP  = Focus(3)
-	RESET = Focus(4)
-	QUIT  = Focus(5)
-)
+// undoReset brings back the timers of the last reset. Running ones pick up
+// from where they were when it happened.
+func (m *model) undoReset() {
+	if len(m.undoBuffer.timers) == 0 {
+		m.setToast(m.text.Toast.NothingToUndo)
+		return
+	}
+	now := m.nowFunc()
+	for _, t := range m.undoBuffer.timers {
+		if t.Running && t.CountUp {
+			t.StartedAt = now.Add(-t.Elapsed)
+		} else if t.Running {
+			t.Deadline = now.Add(t.Remaining)
+		}
+	}
+	m.timers = m.undoBuffer.timers
+	m.seqHead = m.undoBuffer.seqHead
+	m.sequence = m.undoBuffer.sequence
+	m.undoBuffer = undoState{}
+	m.startWaiting()
+	m.keepSelection()
+	m.setToast(fmt.Sprintf(m.text.Toast.Restored, len(m.timers)))
+}
 
-type Timer struct {
-	ID        int
-	Duration  time.Duration
-	Remaining time.Duration
-	Running   bool
-	Finished  bool
-	Alarming  bool // Active alarm state (blinking/ringing)
+// clearFinished removes every finished timer, leaving running and paused
+// ones. The sound stops only if it was ringing for a removed timer.
+func (m *model) clearFinished() {
+	ringing := false
+	m.timers = slices.DeleteFunc(m.timers, func(t *Timer) bool {
+		ringing = ringing || t.Alarming && t.Finished
+		return t.Finished
+	})
+	if ringing && !m.anyAlarming() && m.alarmCancel != nil {
+		m.alarmCancel()
+		m.alarmCancel = nil
+	}
+	m.keepSelection()
 }
 
-type model struct {
-	textInput   textinput.Model
-	timers      []*Timer
-	nextID      int // Keeping nextID if needed, though GetNewID implies calculation
-	blink       bool
-	width       int
-	height      int
-	focusIndex  Focus
-	focusState  Focus
-	alarmCancel context.CancelFunc // To stop the playing sound
+// keepSelection moves the highlight to the first row when the selected
+// timer was removed, or off the list once it is empty.
+func (m *model) keepSelection() {
+	if m.selectedTimer() != nil {
+		return
+	}
+	if shown := m.displayTimers(); len(shown) > 0 {
+		m.selectedID = shown[0].ID
+	} else if m.focusIndex == LIST {
+		m.leaveList()
+	}
 }
 
-func initialModel(initialDuration time.Duration) model {
-	ti := textinput.New()
-	ti.Placeholder = "10s (e.g. 5m, 1h30m)"
-	ti.Focus()
-	ti.CharLimit = 20
-	ti.Width = 30
+// leaveList moves the focus off a list with nothing left to select: to Add,
+// or back to the input in compact view, where the buttons are hidden.
+func (m *model) leaveList() {
+	if m.compact {
+		m.focusIndex = INPUT
+		m.textInput.Focus()
+		return
+	}
+	m.focusIndex = ADD
+}
 
-	timers := []*Timer{}
-	nextID := 1
-	if initialDuration > 0 {
-		timers = append(timers, &Timer{
-			ID:        1,
-			Duration:  initialDuration,
-			Remaining: initialDuration,
-			Running:   true,
-			Finished:  false,
-			Alarming:  false,
-		})
-		nextID = 2
+// restartSelected runs the selected finished timer again from its original
+// duration, stopping the sound if it was the one ringing.
+func (m *model) restartSelected() {
+	t := m.selectedTimer()
+	if t == nil || !t.Finished {
+		return
+	}
+	m.restartTimer(t)
+	if !m.anyAlarming() && m.alarmCancel != nil {
+		m.alarmCancel()
+		m.alarmCancel = nil
 	}
+}
 
-	return model{
-		textInput:  ti,
-		focusIndex: INPUT,
-		timers:     timers,
-		nextID:     nextID,
+// snooze restarts a finished timer for the snooze period, stopping the
+// alarm sound unless other timers still ring. It rings again normally when
+// the snooze runs out.
+func (m *model) snooze(t *Timer) {
+	t.Finished = false
+	t.Alarming = false
+	t.Finishing = false
+	t.Remaining = m.cfg.snooze()
+	t.RunStart = m.nowFunc()
+	m.startTimer(t)
+	if !m.anyAlarming() && m.alarmCancel != nil {
+		m.alarmCancel()
+		m.alarmCancel = nil
 	}
+	m.setToast(fmt.Sprintf(m.text.Toast.Snoozed, m.timerName(t), t.Remaining))
 }
 
-func (m model) Init() tea.Cmd {
-	return tea.Batch(
-		textinput.Blink,
-		tickCmd(),
-		blinkCmd(),
-	)
+// toggleSelected pauses the selected timer, or resumes it if it is paused.
+func (m *model) toggleSelected() {
+	t := m.selectedTimer()
+	if t == nil || t.Finished || t.Queued {
+		return
+	}
+	if t.Running || t.Waiting {
+		t.pause(m.nowFunc())
+		t.Waiting = false
+	} else {
+		m.startTimer(t)
+	}
 }
 
-type tickMsg time.Time
-type blinkMsg time.Time
+// deleteSelected removes the selected timer and moves the highlight to the
+// row that took its place, or the one above when it was the last row.
+func (m *model) deleteSelected() {
+	t := m.selectedTimer()
+	if t == nil {
+		return
+	}
+	row := slices.Index(m.displayTimers(), t)
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+	m.timers = slices.DeleteFunc(m.timers, func(o *Timer) bool { return o == t })
+	m.sequence = slices.DeleteFunc(m.sequence, func(o *Timer) bool { return o == t })
+	if m.seqHead == t {
+		m.advanceSequence()
+	}
+	if t.Alarming && !m.anyAlarming() && m.alarmCancel != nil {
+		m.alarmCancel() // It was the one ringing
+		m.alarmCancel = nil
+	}
+	if t.Running {
+		m.startWaiting() // Its max_running slot is free
+	}
+
+	if !m.listVisible() {
+		m.leaveList()
+		return
+	}
+	shown := m.displayTimers()
+	m.selectedID = shown[min(row, len(shown)-1)].ID
 }
 
-func blinkCmd() tea.Cmd {
-	return tea.Tick(500*time.Millisecond, func(t time.Time) tea.Msg {
-		return blinkMsg(t)
-	})
+// nearestRunning returns the running timer with the least time left.
+func (m model) nearestRunning() *Timer {
+	var nearest *Timer
+	for _, t := range m.timers {
+		if t.Running && !t.CountUp && (nearest == nil || t.Remaining < nearest.Remaining) {
+			nearest = t
+		}
+	}
+	return nearest
 }
 
-func playSound(ctx context.Context) {
-	// Try standard sound paths
-	soundFiles := []string{
-		"/usr/share/sounds/freedesktop/stereo/alarm-clock-elapsed.oga",
-		"/usr/share/sounds/freedesktop/stereo/complete.oga",
+// windowTitle shows one timer's countdown in the terminal title: the
+// selected timer with window_title "selected", otherwise (or with nothing
+// selected) the running timer that finishes soonest.
+func (m model) windowTitle() string {
+	t := m.nearestRunning()
+	if m.cfg.WindowTitle == "selected" {
+		if sel := m.selectedTimer(); sel != nil {
+			t = sel
+		}
+	}
+	if t == nil {
+		return "TUI Timer"
 	}
+	if t.Finished {
+		return fmt.Sprintf("%s %s", m.text.TimesUp, m.timerName(t))
+	}
+	if t.CountUp {
+		return fmt.Sprintf("⏱ %s %s", t.Elapsed.Round(time.Second), m.timerName(t))
+	}
+	return fmt.Sprintf("%s %s", t.Remaining.Round(time.Second), m.timerName(t))
+}
 
-	for _, sf := range soundFiles {
-		if _, err := os.Stat(sf); err == nil {
-			// Run with context so we can kill it
-			_ = exec.CommandContext(ctx, "paplay", sf).Run()
-			return
+// setStyles switches every style, including the input's, at once.
+func (m *model) setStyles(st styles) {
+	m.styles = st
+	m.textInput.PromptStyle = st.Input
+	m.textInput.TextStyle = st.Input
+}
+
+// lastFocus is the rightmost button: Quit, or the last quick-add button.
+func (m model) lastFocus() Focus {
+	return QUIT + Focus(len(m.cfg.Buttons))
+}
+
+func (m model) anyAlarming() bool {
+	for _, t := range m.timers {
+		if t.Alarming {
+			return true
 		}
 	}
-	// Fallback to bell
-	fmt.Print("\a")
+	return false
 }
 
-func (m model) GetNewID() int {
-	if len(m.timers) == 0 {
-		return 1
+// importantAlarming reports whether a high-priority timer is still ringing.
+func (m model) importantAlarming() bool {
+	for _, t := range m.timers {
+		if t.Alarming && t.Priority == HIGH {
+			return true
+		}
 	}
-	return m.timers[len(m.timers)-1].ID + 1
+	return false
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -144,61 +1379,364 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.overallBar.Width = min(40, max(10, msg.Width-4))
+		m.timerBar.Width = min(20, max(5, msg.Width/4))
+		m.help.Width = msg.Width
+	case tea.MouseMsg:
+		return m.click(msg)
+
 	case tea.KeyMsg:
-		// Dismiss any active alarms on key press and stop sound
-		anyAlarming := false
-		for _, t := range m.timers {
-			if t.Alarming {
-				t.Alarming = false
-				anyAlarming = true
+		// ctrl+c always quits, even over a ringing alarm
+		if key.Matches(msg, m.keys.ForceQuit) {
+			m.dismissAlarms()
+			return m, tea.Quit
+		}
+
+		// s snoozes instead of dismissing: the selected timer if it is
+		// ringing, otherwise the one that has rung longest, like any key
+		if key.Matches(msg, m.keys.Snooze) && m.anyAlarming() {
+			t := m.selectedTimer()
+			if t == nil || !t.Alarming {
+				t = m.oldestAlarming()
 			}
+			m.snooze(t)
+			return m, nil
 		}
 
-		if m.alarmCancel != nil {
-			m.alarmCancel() // Kill the sound process
-			m.alarmCancel = nil
+		// m mutes without dismissing, so a ringing alarm keeps blinking
+		if key.Matches(msg, m.keys.Mute) && (m.focusIndex != INPUT || m.anyAlarming()) {
+			m.muted = !m.muted
+			if m.muted && m.alarmCancel != nil {
+				m.alarmCancel()
+				m.alarmCancel = nil
+			}
+			return m, nil
 		}
 
-		if anyAlarming {
+		// r on the selected ringing timer runs it again rather than
+		// only silencing it
+		if t := m.selectedTimer(); key.Matches(msg, m.keys.ResetOne) && m.focusIndex == LIST && t != nil && t.Alarming {
+			m.restartSelected()
 			return m, nil
 		}
 
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return m, tea.Quit
-		case "tab", "shift+tab", "left", "right", "up", "down":
-			s := msg.String()
+		// A key press (or only the dismiss keys, if configured) silences
+		// the oldest ringing timer, so several alarms are acknowledged one
+		// by one; D silences them all. Navigation keys go on to navigate;
+		// any other key is swallowed, so a q pressed to silence an alarm
+		// does not also quit.
+		if key.Matches(msg, m.keys.DismissAll) && m.dismissAlarms() {
+			return m, nil
+		}
+		dismissKey := len(m.keys.Dismiss.Keys()) == 0 || key.Matches(msg, m.keys.Dismiss)
+		if dismissKey && m.dismissOne() && !m.navigates(msg) {
+			return m, nil
+		}
+
+		// A pending y/n question takes every key until it is answered
+		if m.confirming != CONFIRM_NONE {
+			switch {
+			case key.Matches(msg, m.keys.Yes):
+				return m.answerConfirm()
+			case key.Matches(msg, m.keys.No):
+				m.confirming = CONFIRM_NONE
+				m.confirmPrompt = ""
+			}
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Zoom) && (m.zoomID != 0 || m.focusIndex == LIST):
+			// Show the selected timer full screen, or go back to the list
+			if m.zoomID != 0 {
+				m.zoomID = 0
+			} else if t := m.selectedTimer(); t != nil {
+				m.zoomID = t.ID
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Cancel) && m.zoomID != 0:
+			m.zoomID = 0
+			return m, nil
+		case key.Matches(msg, m.keys.Cancel) && m.editingID != 0:
+			m.cancelEdit()
+			m.focusIndex = INPUT
+			return m, m.textInput.Focus()
+		case key.Matches(msg, m.keys.Edit) && m.focusIndex == LIST:
+			// Change the selected timer's time in the input
+			return m, m.editSelected()
+		case key.Matches(msg, m.keys.Quit):
+			// q is an ordinary letter while typing a label
+			if m.focusIndex != INPUT && m.cfg.QuitKey != "off" {
+				return m.requestQuit(m.cfg.QuitKey == "confirm")
+			}
+		case key.Matches(msg, m.keys.NewestFirst):
+			// Toggle newest-first ordering (not while typing a duration)
+			if m.focusIndex != INPUT {
+				m.newestFirst = !m.newestFirst
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Recent):
+			// Re-add a recently used duration
+			i := slices.Index(m.keys.Recent.Keys(), msg.String())
+			if i < len(m.recent) {
+				m.addTimer(timerSpec{Duration: m.recent[i]})
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Preset):
+			// Add a preset duration, like typing it in
+			if m.focusIndex != INPUT {
+				i := slices.Index(m.keys.Preset.Keys(), msg.String())
+				if i >= 0 && i < len(presets) {
+					m.addTimer(timerSpec{Duration: presets[i]})
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.VolumeUp), key.Matches(msg, m.keys.VolumeDown):
+			if m.focusIndex != INPUT {
+				step := volumeStep
+				if key.Matches(msg, m.keys.VolumeDown) {
+					step = -step
+				}
+				m.volume = min(100, max(0, m.volume+step))
+				m.setToast(fmt.Sprintf(m.text.Toast.Volume, m.volume))
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Theme):
+			// Switch between the light and dark themes
+			if m.focusIndex != INPUT {
+				m.theme = map[string]string{"dark": "light", "light": "dark"}[m.theme]
+				m.setStyles(newStyles(themes[m.theme], m.cfg.Styles))
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Restart):
+			// Restart every finished timer from its full duration
+			if m.focusIndex != INPUT {
+				for _, t := range m.timers {
+					if t.Finished {
+						m.restartTimer(t)
+					}
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Snapshot):
+			// Snapshot the current timers for --diff-sessions
+			if m.focusIndex != INPUT {
+				name, err := saveSession(m.timers, m.nowFunc())
+				if err != nil {
+					m.setToast(fmt.Sprintf(m.text.Toast.SessionFailed, err))
+				} else {
+					m.setToast(fmt.Sprintf(m.text.Toast.SessionSaved, name))
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Group):
+			// Group the list into Running / Paused / Finished sections
+			if m.focusIndex != INPUT {
+				m.grouped = !m.grouped
+				m.byGroup = false
+				if m.focusIndex == LIST && !m.listVisible() {
+					m.leaveList()
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.ByGroup):
+			// Split the list into the timers' named groups
+			if m.focusIndex != INPUT {
+				m.byGroup = !m.byGroup
+				m.grouped = false
+				if m.focusIndex == LIST && !m.listVisible() {
+					m.leaveList()
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.CollapseGroup):
+			// Collapse the selected timer's group down to its header
+			if t := m.selectedTimer(); m.byGroup && m.focusIndex == LIST && t != nil {
+				m.toggleGroup(t.Group)
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.ExpandGroups):
+			// Collapsed groups have no rows to select, so they open all at once
+			if m.byGroup {
+				clear(m.collapsedGroups)
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.CollapseRunning, m.keys.CollapsePaused, m.keys.CollapseFinished):
+			// Collapse or expand one section of the grouped list
+			if m.grouped {
+				section := SECTION_RUNNING
+				if key.Matches(msg, m.keys.CollapsePaused) {
+					section = SECTION_PAUSED
+				} else if key.Matches(msg, m.keys.CollapseFinished) {
+					section = SECTION_FINISHED
+				}
+				m.toggleSection(section)
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Words):
+			// Toggle remaining time between exact and words
+			if m.focusIndex != INPUT {
+				m.showWords = !m.showWords
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Elapsed):
+			// Toggle elapsed time next to the remaining time
+			if m.focusIndex != INPUT {
+				m.showElapsed = !m.showElapsed
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Compact):
+			// Switch between the full view and the few-line one
+			if m.focusIndex != INPUT {
+				m.compact = !m.compact
+				if m.compact && m.focusIndex > LIST {
+					m.focusIndex = LIST // The buttons are hidden
+					m.keepSelection()
+				}
+				if m.compact && m.focusIndex != LIST {
+					// No timers to select, so back to typing one
+					m.focusIndex = INPUT
+					return m, m.textInput.Focus()
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Heatmap):
+			// Show the session's finish heatmap in place of the list
+			if m.focusIndex != INPUT {
+				m.showHeatmap = !m.showHeatmap
+				if m.focusIndex == LIST {
+					m.leaveList()
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Minimize):
+			// Collapse the list into a summary line, or expand it again
+			if m.focusIndex != INPUT {
+				m.minimized = !m.minimized
+				if m.focusIndex == LIST {
+					m.leaveList()
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Reference):
+			// Mark T-0 now; timers show their finish relative to it
+			if m.focusIndex != INPUT {
+				m.referenceTime = m.nowFunc()
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Copy):
+			// Copy the selected timer's time left, for pasting into chat
+			if t := m.selectedTimer(); m.focusIndex == LIST && t != nil {
+				return m, copyCmd(m.copyText(t))
+			}
+		case key.Matches(msg, m.keys.Detail):
+			// Toggle the detail pane for the selected timer
+			if m.focusIndex != INPUT {
+				m.showDetail = !m.showDetail
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Finish):
+			// Finish the selected timer now, as if it had elapsed
+			if m.focusIndex == LIST {
+				if t := m.selectedTimer(); t != nil && !t.Finished && !t.Queued {
+					return m, m.finish([]*Timer{t}, m.nowFunc())
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Pause):
+			// Pause whichever running timer finishes soonest
+			if m.focusIndex != INPUT {
+				if t := m.nearestRunning(); t != nil {
+					t.pause(m.nowFunc())
+					m.setToast(fmt.Sprintf(m.text.Toast.Paused, m.timerName(t)))
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Sort):
+			// Cycle sort order; the selection stays on the same timer
+			if m.focusIndex != INPUT {
+				m.sortMode = (m.sortMode + 1) % (SORT_LATEST + 1)
+				m.setToast(fmt.Sprintf(m.text.Toast.Sorted, m.sortMode))
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.MoveUp, m.keys.MoveDown):
+			// Move the selected timer by hand, in creation order only
+			if m.focusIndex == LIST {
+				delta := 1
+				if key.Matches(msg, m.keys.MoveUp) {
+					delta = -1
+				}
+				m.moveSelected(delta)
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Next, m.keys.Prev, m.keys.Left, m.keys.Right, m.keys.Up, m.keys.Down):
+			if m.focusIndex == INPUT && msg.Type == tea.KeyRunes {
+				break // hjkl are letters in a label while typing
+			}
+			if m.focusIndex == INPUT && key.Matches(msg, m.keys.Up, m.keys.Down) && m.recall(key.Matches(msg, m.keys.Up)) {
+				return m, nil
+			}
+			var s string
+			switch {
+			case key.Matches(msg, m.keys.Next):
+				s = "tab"
+			case key.Matches(msg, m.keys.Prev):
+				s = "shift+tab"
+			case key.Matches(msg, m.keys.Left):
+				s = "left"
+			case key.Matches(msg, m.keys.Right):
+				s = "right"
+			case key.Matches(msg, m.keys.Up):
+				s = "up"
+			default:
+				s = "down"
+			}
+			if m.cfg.rtl() {
+				// The button row is mirrored, so left and right swap too
+				switch s {
+				case "left":
+					s = "right"
+				case "right":
+					s = "left"
+				}
+			}
 
 			switch s {
 			case "tab":
 				m.focusIndex++
-				if m.focusIndex > QUIT {
+				if m.focusIndex == LIST && !m.listVisible() {
+					m.focusIndex++
+				}
+				if m.focusIndex > m.lastFocus() {
 					m.focusIndex = INPUT
 				}
 
 			case "shift+tab":
 				m.focusIndex--
+				if m.focusIndex == LIST && !m.listVisible() {
+					m.focusIndex--
+				}
 				if m.focusIndex < INPUT {
-					m.focusIndex = QUIT
+					m.focusIndex = m.lastFocus()
 				}
 
 			case "left":
-				if m.focusIndex == INPUT {
+				if m.focusIndex == INPUT || m.focusIndex == LIST {
 					break
 				}
 				if m.focusIndex == ADD {
-					m.focusIndex = QUIT
-					m.focusState = QUIT
+					m.focusIndex = m.lastFocus()
+					m.focusState = m.focusIndex
 					break
 				}
 				m.focusIndex--
 				m.focusState = m.focusIndex
 
 			case "right":
-				if m.focusIndex == INPUT {
+				if m.focusIndex == INPUT || m.focusIndex == LIST {
 					break
 				}
-				if m.focusIndex == QUIT {
+				if m.focusIndex == m.lastFocus() {
 					m.focusIndex = ADD
 					m.focusState = ADD
 					break
@@ -207,13 +1745,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.focusState = m.focusIndex
 
 			case "up":
-				if m.focusIndex > INPUT {
-					m.focusIndex = INPUT
+				if m.focusIndex == LIST {
+					if !m.moveSelection(-1) {
+						m.focusIndex = INPUT
+					}
+				} else if m.focusIndex > LIST {
+					if m.listVisible() {
+						m.focusIndex = LIST
+					} else {
+						m.focusIndex = INPUT
+					}
 				}
 
 			case "down":
-				if m.focusIndex == INPUT {
-					if m.focusState > INPUT {
+				if m.focusIndex == INPUT && m.listVisible() {
+					m.focusIndex = LIST
+				} else if m.focusIndex == INPUT || (m.focusIndex == LIST && !m.moveSelection(1)) {
+					if m.focusState > LIST {
 						m.focusIndex = m.focusState
 					} else {
 						m.focusIndex = ADD
@@ -221,10 +1769,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 
-			if m.focusIndex > QUIT {
+			if m.compact && m.focusIndex > LIST {
+				// No buttons to land on in the compact view
+				if s == "shift+tab" && m.listVisible() {
+					m.focusIndex = LIST
+				} else {
+					m.focusIndex = INPUT
+				}
+			}
+
+			if m.focusIndex == LIST && m.selectedTimer() == nil {
+				m.selectedID = m.displayTimers()[0].ID
+			}
+
+			if m.focusIndex > m.lastFocus() {
 				m.focusIndex = INPUT
 			} else if m.focusIndex < INPUT {
-				m.focusIndex = QUIT
+				m.focusIndex = m.lastFocus()
 			}
 
 			if m.focusIndex == INPUT {
@@ -234,189 +1795,323 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, cmd
 
-		case "enter":
-			if m.focusIndex == INPUT {
-				parsed, err := time.ParseDuration(m.textInput.Value())
-				if err == nil && parsed > 0 {
-					newTimer := &Timer{
-						ID:        m.GetNewID(),
-						Duration:  parsed,
-						Remaining: parsed,
-						Running:   true,
-						Finished:  false,
-						Alarming:  false,
-					}
-					m.timers = append(m.timers, newTimer)
-					m.textInput.SetValue("")
-				}
-			} else if m.focusIndex == ADD {
-				parsed, err := time.ParseDuration(m.textInput.Value())
-				if err == nil && parsed > 0 {
-					newTimer := &Timer{
-						ID:        m.GetNewID(),
-						Duration:  parsed,
-						Remaining: parsed,
-						Running:   true,
-						Finished:  false,
-						Alarming:  false,
-					}
-					m.timers = append(m.timers, newTimer)
-					m.textInput.SetValue("")
-				}
-			} else if m.focusIndex == START {
-				// Global Resume
-				for _, t := range m.timers {
-					if !t.Finished {
-						t.Running = true
-					}
-				}
-			} else if m.focusIndex == STOP {
-				// Global Pause
-				for _, t := range m.timers {
-					t.Running = false
-				}
-			} else if m.focusIndex == RESET {
-				if m.alarmCancel != nil {
-					m.alarmCancel()
-					m.alarmCancel = nil
-				}
-				m.timers = []*Timer{}
-			} else if m.focusIndex == QUIT {
-				if m.alarmCancel != nil {
-					m.alarmCancel()
+		case key.Matches(msg, m.keys.ResetOne):
+			// Run the selected finished timer again from its full duration
+			if m.focusIndex == LIST {
+				m.restartSelected()
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Snooze):
+			// Snooze the selected finished timer
+			if m.focusIndex == LIST {
+				if t := m.selectedTimer(); t != nil && t.Finished {
+					m.snooze(t)
 				}
-				return m, tea.Quit
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Delete):
+			// Delete just the selected timer
+			if m.focusIndex == LIST {
+				m.deleteSelected()
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Toggle):
+			// Pause or resume just the selected timer; on the buttons,
+			// every timer
+			if m.focusIndex == LIST {
+				m.toggleSelected()
+				return m, nil
+			}
+			if m.focusIndex != INPUT {
+				return m.pauseAll()
 			}
+		case key.Matches(msg, m.keys.PauseAll):
+			if m.focusIndex != INPUT {
+				return m.pauseAll()
+			}
+		case key.Matches(msg, m.keys.Add):
+			if m.focusIndex != INPUT {
+				return m.press(ADD)
+			}
+		case key.Matches(msg, m.keys.Start):
+			if m.focusIndex != INPUT {
+				return m.press(START)
+			}
+		case key.Matches(msg, m.keys.Stop):
+			if m.focusIndex != INPUT {
+				return m.press(STOP)
+			}
+		case key.Matches(msg, m.keys.Reset):
+			if m.focusIndex != INPUT {
+				return m.press(RESET)
+			}
+		case key.Matches(msg, m.keys.Clear):
+			if m.focusIndex != INPUT {
+				return m.press(CLEAR)
+			}
+		case key.Matches(msg, m.keys.Undo):
+			// Bring back the timers of the last reset
+			if m.focusIndex != INPUT {
+				m.undoReset()
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Help):
+			// Anywhere but mid-typing, since ? could be part of a label
+			if m.focusIndex != INPUT || m.textInput.Value() == "" {
+				m.help.ShowAll = !m.help.ShowAll
+				return m, nil
+			}
+		case key.Matches(msg, m.keys.Select):
+			return m.press(m.focusIndex)
 		}
 
 	case tickMsg:
-		anyFinishedNow := false
+		now := time.Time(msg)
+		finished, checkpoints := tick(m.timers, now, time.Duration(m.cfg.GracePeriod))
+		for _, t := range checkpoints {
+			m.setToast(fmt.Sprintf("#%d %s", t.ID, m.text.Checkpoint))
+		}
+		if m.toast != "" && now.After(m.toastUntil) {
+			m.toast = ""
+		}
+		m.removeDismissed(now)
+		cmds := []tea.Cmd{m.finish(finished, now), m.nextTick()}
+		if m.cfg.WindowTitle != "" {
+			cmds = append(cmds, tea.SetWindowTitle(m.windowTitle()))
+		}
+		return m, tea.Batch(cmds...)
+
+	case dismissAlarmMsg:
+		m.dismissAlarms()
+		return m, nil
+
+	case commandResultMsg:
+		if msg.Err != nil {
+			m.setToast(fmt.Sprintf(m.text.Toast.CommandFailed, msg.ID, msg.Err))
+		}
+		return m, nil
+
+	case webhookResultMsg:
+		if msg.Err != nil {
+			m.setToast(fmt.Sprintf(m.text.Toast.WebhookFailed, msg.ID, msg.Err))
+		}
+		return m, nil
+
+	case soundResultMsg:
+		m.lastSound = soundResult(msg)
+		if msg.Err != nil && !msg.Canceled {
+			m.setToast(fmt.Sprintf(m.text.Toast.SoundFailed, msg.Player, msg.Err))
+		} else if msg.AnnounceErr != nil && !msg.Canceled {
+			m.setToast(fmt.Sprintf(m.text.Toast.AnnounceFailed, msg.AnnounceErr))
+		}
+		return m, nil
+
+	case clipboardMsg:
+		if msg.Err != nil {
+			m.setToast(fmt.Sprintf(m.text.Toast.CopyFailed, msg.Err))
+		} else {
+			m.setToast(fmt.Sprintf(m.text.Toast.Copied, msg.Text))
+		}
+		return m, nil
+
+	case alarmRepeatMsg:
+		var alarming []*Timer
 		for _, t := range m.timers {
-			if t.Running && t.Remaining > 0 {
-				t.Remaining -= time.Second
-				if t.Remaining <= 0 {
-					t.Running = false
-					t.Remaining = 0
-					t.Finished = true
-					t.Alarming = true
-					anyFinishedNow = true
-				}
+			if t.Alarming {
+				alarming = append(alarming, t)
 			}
 		}
-		if anyFinishedNow {
-			if m.alarmCancel != nil {
-				m.alarmCancel()
-			}
-			ctx, cancel := context.WithCancel(context.Background())
-			m.alarmCancel = cancel
-			return m, tea.Batch(
-				func() tea.Msg { playSound(ctx); return nil },
-				tickCmd(),
-			)
+		if len(alarming) == 0 {
+			m.repeating = false
+			return m, nil
 		}
-		return m, tickCmd()
+		return m, tea.Batch(m.startAlarm(alarming, true), m.alarmRepeatCmd())
 
 	case blinkMsg:
 		m.blink = !m.blink
-		return m, blinkCmd()
+		return m, m.blinkCmd()
 	}
 
 	if m.focusIndex == INPUT {
+		before := m.textInput.Value()
 		m.textInput, cmd = m.textInput.Update(msg)
+		if m.textInput.Value() != before {
+			m.inputError = ""
+		}
 	}
 	return m, cmd
 }
 
-func (m model) View() string {
-	var s strings.Builder
-
-	// Input
-	s.WriteString("New Timer: ")
-	s.WriteString(m.textInput.View())
-	s.WriteString("\n\n")
-
-	// Timer List
-	if len(m.timers) == 0 {
-		s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("No timers running"))
-		s.WriteString("\n\n")
-	} else {
+// press does what Enter does on button f.
+func (m model) press(f Focus) (tea.Model, tea.Cmd) {
+	if f == INPUT || f == ADD {
+		m.submitInput()
+	} else if f == LIST {
+		m.toggleSelected()
+	} else if f == START {
+		// Global Resume
 		for _, t := range m.timers {
-			s.WriteString(fmt.Sprintf("#%d: ", t.ID))
-			if t.Finished {
-				msg := "Time's Up!"
-				if t.Alarming && m.blink {
-					s.WriteString(alarmStyle.Render(msg))
-				} else {
-					s.WriteString(msg)
-				}
-			} else {
-				status := ""
-				if !t.Running {
-					status = " (Paused)"
-				}
-				s.WriteString(fmt.Sprintf("%s remaining%s", t.Remaining.Round(time.Second), status))
+			if !t.Finished && !t.Queued && !t.Running {
+				m.startTimer(t)
 			}
-			s.WriteString("\n")
 		}
-		s.WriteString("\n")
+	} else if f == STOP {
+		// Global Pause
+		now := m.nowFunc()
+		for _, t := range m.timers {
+			t.pause(now)
+			t.Waiting = false
+		}
+	} else if f == RESET {
+		if len(m.timers) > 0 {
+			m.confirming = CONFIRM_RESET
+			m.confirmPrompt = fmt.Sprintf(m.text.ConfirmReset, len(m.timers))
+		}
+	} else if f == CLEAR {
+		m.clearFinished()
+	} else if f == QUIT {
+		return m.requestQuit(false)
+	} else if f > QUIT {
+		// Quick-add button from the config
+		b := m.cfg.Buttons[f-QUIT-1]
+		m.addTimer(timerSpec{Duration: time.Duration(b.Duration), Label: b.Label, SoundFile: b.SoundFile})
 	}
+	return m, nil
+}
 
-	// Buttons
-	addButton := fmt.Sprintf("[ %s ]", "Add")
-	if m.focusIndex == ADD {
-		addButton = fmt.Sprintf(focusedButton, "Add")
-	} else {
-		addButton = fmt.Sprintf(blurredButton, "Add")
+// pauseAll pauses every timer if any is running, like Stop, and otherwise
+// resumes them all, like Start.
+func (m model) pauseAll() (tea.Model, tea.Cmd) {
+	for _, t := range m.timers {
+		if t.Running || t.Waiting {
+			return m.press(STOP)
+		}
 	}
+	return m.press(START)
+}
 
-	startButton := fmt.Sprintf("[ %s ]", "Start")
-	if m.focusIndex == START {
-		startButton = fmt.Sprintf(focusedButton, "Start")
-	} else {
-		startButton = fmt.Sprintf(blurredButton, "Start")
+// overallProgress is the fraction of total time elapsed across the whole
+// batch, weighted by duration. Finished timers count as complete so the bar
+// never moves backwards as the batch finishes; stopwatches have no
+// duration and are left out.
+func (m model) overallProgress() float64 {
+	var total, elapsed time.Duration
+	for _, t := range m.timers {
+		if t.CountUp {
+			continue
+		}
+		total += t.Duration
+		elapsed += t.Duration - t.Remaining
 	}
-
-	stopButton := fmt.Sprintf("[ %s ]", "Stop")
-	if m.focusIndex == STOP {
-		stopButton = fmt.Sprintf(focusedButton, "Stop")
-	} else {
-		stopButton = fmt.Sprintf(blurredButton, "Stop")
+	if total <= 0 {
+		return 0
 	}
+	return float64(elapsed) / float64(total)
+}
 
-	resetButton := fmt.Sprintf("[ %s ]", "Reset")
-	if m.focusIndex == RESET {
-		resetButton = fmt.Sprintf(focusedButton, "Reset")
-	} else {
-		resetButton = fmt.Sprintf(blurredButton, "Reset")
-	}
+func main() {
+	exportCSV := flag.String("export-csv", "", "write the finished-timer history as CSV to `PATH` and exit")
+	inline := flag.Bool("inline", false, "run without the alternate screen so the output stays in the scrollback")
+	compact := flag.Bool("compact", false, "start in the compact view: input, timers and messages on a few lines")
+	wait := flag.String("wait", "", "count down `DURATION` without the TUI, sound the alarm and exit")
+	diff := flag.Bool("diff-sessions", false, "compare two saved sessions given as arguments and exit")
+	flag.Parse()
 
-	quitButton := fmt.Sprintf("[ %s ]", "Quit")
-	if m.focusIndex == QUIT {
-		quitButton = fmt.Sprintf(focusedButton, "Quit")
-	} else {
-		quitButton = fmt.Sprintf(blurredButton, "Quit")
+	if *diff {
+		if flag.NArg() != 2 {
+			fmt.Println("Usage: --diff-sessions A B")
+			os.Exit(1)
+		}
+		a, err := loadSession(flag.Arg(0))
+		if err != nil {
+			fmt.Printf("Invalid session: %v\n", err)
+			os.Exit(1)
+		}
+		b, err := loadSession(flag.Arg(1))
+		if err != nil {
+			fmt.Printf("Invalid session: %v\n", err)
+			os.Exit(1)
+		}
+		diffSessions(os.Stdout, a, b)
+		return
 	}
 
-	s.WriteString(fmt.Sprintf("%s  %s  %s  %s  %s\n\n", addButton, startButton, stopButton, resetButton, quitButton))
+	if *exportCSV != "" {
+		if err := exportHistoryCSV(*exportCSV); err != nil {
+			fmt.Printf("Export failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	s.WriteString(helpStyle.Render("(Tab to navigate, Enter to select)"))
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Printf("Invalid config: %v\n", err)
+		os.Exit(1)
+	}
 
-	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, s.String())
-}
+	text, err := loadLocale(cfg.Locale)
+	if err != nil {
+		fmt.Printf("Invalid locale %q: %v\n", cfg.Locale, err)
+		os.Exit(1)
+	}
 
-func main() {
-	var duration time.Duration
-	if len(os.Args) > 1 {
-		var err error
-		duration, err = time.ParseDuration(os.Args[1])
+	if *wait != "" {
+		spec, err := parseTimerInput(*wait)
+		if err == nil && spec.Duration <= 0 {
+			err = errors.New("--wait needs a countdown, not a stopwatch")
+		}
 		if err != nil {
 			fmt.Printf("Invalid duration: %v\n", err)
 			os.Exit(1)
 		}
+		runWait(spec.Duration, text, configuredSound(cfg.SoundFile), cfg.volume())
+		return
+	}
+
+	// Every argument starts a timer right away
+	var durations []time.Duration
+	for _, arg := range flag.Args() {
+		d, err := parseTimerDuration(arg)
+		if err != nil || d <= 0 {
+			fmt.Printf("Invalid duration %q\n", arg)
+			fmt.Println("Usage: Timer [flags] [DURATION...], e.g. Timer 10m 1h30m")
+			os.Exit(1)
+		}
+		durations = append(durations, d)
+	}
+	// A missing state file just means nothing to restore; a corrupt one
+	// is reported and left for the next quit to overwrite
+	saved, loadErr := loadState(statePath())
+	if loadErr != nil {
+		saved = savedState{} // Never restore half of a corrupt file
+	}
+	m := initialModel(cfg, text, saved, durations)
+	m.inline = *inline
+	m.compact = *compact
+	if loadErr != nil && !errors.Is(loadErr, os.ErrNotExist) {
+		msg := fmt.Sprintf(m.text.Toast.RestoreFailed, loadErr)
+		if m.toast != "" {
+			msg += "; " + m.toast // Keep the missing-sound warning too
+		}
+		m.setToast(msg)
+	}
+
+	var opts []tea.ProgramOption
+	if !m.inline {
+		opts = append(opts, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, opts...)
+	watchDismissSignal(p)
+	watchHangup(p)
+	final, runErr := p.Run()
+	if final, ok := final.(model); ok {
+		if err := saveState(statePath(), newSavedState(final, final.nowFunc())); err != nil {
+			fmt.Printf("Could not save timers: %v\n", err)
+		}
 	}
-	p := tea.NewProgram(initialModel(duration), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error: %v", err)
+	if runErr != nil {
+		fmt.Printf("Error: %v", runErr)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,463 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func alarmingModel() model {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	t := m.addTimer(timerSpec{Duration: time.Minute})
+	t.Remaining = 0
+	t.Running = false
+	t.Finished = true
+	t.Alarming = true
+	return m
+}
+
+func press(m model, key string) (model, tea.Cmd) {
+	msg, ok := map[string]tea.KeyMsg{
+		"tab":    {Type: tea.KeyTab},
+		"enter":  {Type: tea.KeyEnter},
+		"ctrl+c": {Type: tea.KeyCtrlC},
+	}[key]
+	if !ok {
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	next, cmd := m.Update(msg)
+	return next.(model), cmd
+}
+
+func quits(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func TestAlarmKeyOnlyDismisses(t *testing.T) {
+	m := alarmingModel()
+
+	m, _ = press(m, "x")
+	if m.textInput.Value() != "" {
+		t.Errorf("input got %q from the key that dismissed the alarm", m.textInput.Value())
+	}
+	if m.anyAlarming() {
+		t.Error("alarm still ringing after a key press")
+	}
+}
+
+func TestNavigationDismissesAndMoves(t *testing.T) {
+	m := alarmingModel()
+
+	m, _ = press(m, "tab")
+	if m.anyAlarming() {
+		t.Error("alarm still ringing after tab")
+	}
+	if m.focusIndex == INPUT {
+		t.Error("tab that dismissed the alarm did not also move focus")
+	}
+}
+
+func TestEnterDuringAlarmDoesNotSubmit(t *testing.T) {
+	m := alarmingModel()
+	m.textInput.SetValue("5m")
+
+	m, _ = press(m, "enter")
+	if len(m.timers) != 1 {
+		t.Errorf("got %d timers, enter should only dismiss the alarm", len(m.timers))
+	}
+	if m.textInput.Value() != "5m" {
+		t.Errorf("input cleared to %q", m.textInput.Value())
+	}
+}
+
+func TestFirstKeyAfterAlarm(t *testing.T) {
+	m := alarmingModel()
+
+	m, _ = press(m, "tab")
+	m, _ = press(m, "tab")
+	if m.focusIndex == INPUT {
+		t.Error("tab after the alarm was dismissed did not move focus")
+	}
+}
+
+func TestQDuringAlarmDoesNotQuit(t *testing.T) {
+	m := alarmingModel()
+	m.focusIndex = ADD // q is typed, not a command, in the input
+
+	m, cmd := press(m, "q")
+	if quits(cmd) {
+		t.Fatal("q that dismissed an alarm also quit")
+	}
+	if _, cmd = press(m, "q"); !quits(cmd) {
+		t.Error("second q did not quit")
+	}
+}
+
+func TestQInLabelDoesNotQuit(t *testing.T) {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	m.addTimer(timerSpec{Duration: time.Minute}) // So q would ask first
+
+	for _, r := range "5m quick tea" {
+		m, _ = press(m, string(r))
+	}
+	if m.confirming != CONFIRM_NONE {
+		t.Errorf("typing a label asked %q", m.confirmPrompt)
+	}
+	if got := m.textInput.Value(); got != "5m quick tea" {
+		t.Errorf("input = %q, want the whole label typed", got)
+	}
+}
+
+func TestCtrlCQuitsDuringAlarm(t *testing.T) {
+	m := alarmingModel()
+
+	if _, cmd := press(m, "ctrl+c"); !quits(cmd) {
+		t.Error("ctrl+c over an alarm did not quit")
+	}
+}
+
+func runningTimer(id int, remaining time.Duration, now time.Time) *Timer {
+	return &Timer{
+		ID:        id,
+		Duration:  remaining,
+		Remaining: remaining,
+		Deadline:  now.Add(remaining),
+		Running:   true,
+	}
+}
+
+func TestTickCountsDown(t *testing.T) {
+	now := time.Now()
+	timer := runningTimer(1, time.Minute, now)
+
+	finished, _ := tick([]*Timer{timer}, now.Add(20*time.Second), 0)
+	if len(finished) != 0 {
+		t.Errorf("got %d finished timers, want none", len(finished))
+	}
+	if timer.Remaining != 40*time.Second {
+		t.Errorf("remaining = %s, want 40s", timer.Remaining)
+	}
+	if !timer.Running {
+		t.Error("timer stopped mid-countdown")
+	}
+}
+
+func TestTickFinishes(t *testing.T) {
+	now := time.Now()
+	timer := runningTimer(1, time.Minute, now)
+
+	// A late tick lands past the deadline; remaining never goes negative
+	finished, _ := tick([]*Timer{timer}, now.Add(61*time.Second), 0)
+	if len(finished) != 1 || finished[0] != timer {
+		t.Fatalf("finished = %v, want the timer", finished)
+	}
+	if timer.Remaining != 0 {
+		t.Errorf("remaining = %s, want 0", timer.Remaining)
+	}
+
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	m.timers = []*Timer{timer}
+	m.finish(finished, now.Add(61*time.Second))
+	if !timer.Finished || !timer.Alarming || timer.Running {
+		t.Errorf("finished=%v alarming=%v running=%v, want a finished, alarming, stopped timer",
+			timer.Finished, timer.Alarming, timer.Running)
+	}
+}
+
+func TestTickGracePeriod(t *testing.T) {
+	now := time.Now()
+	timer := runningTimer(1, time.Minute, now)
+
+	tick([]*Timer{timer}, now.Add(55*time.Second), 10*time.Second)
+	if !timer.Finishing {
+		t.Error("timer not finishing inside the grace period")
+	}
+}
+
+func TestTickSkipsPaused(t *testing.T) {
+	now := time.Now()
+	timer := runningTimer(1, time.Minute, now)
+	timer.pause(now.Add(10 * time.Second))
+
+	finished, _ := tick([]*Timer{timer}, now.Add(2*time.Minute), 0)
+	if len(finished) != 0 {
+		t.Error("paused timer finished")
+	}
+	if timer.Remaining != 50*time.Second {
+		t.Errorf("remaining = %s, want the 50s left when paused", timer.Remaining)
+	}
+}
+
+func TestTickCheckpoint(t *testing.T) {
+	now := time.Now()
+	timer := runningTimer(1, time.Minute, now)
+	timer.PauseAt = 30 * time.Second
+
+	_, checkpoints := tick([]*Timer{timer}, now.Add(31*time.Second), 0)
+	if len(checkpoints) != 1 || timer.Running || !timer.CheckpointHit {
+		t.Errorf("checkpoints=%d running=%v, want the timer paused at its checkpoint",
+			len(checkpoints), timer.Running)
+	}
+}
+
+func TestNewID(t *testing.T) {
+	tests := []struct {
+		ids  []int
+		next int
+		want int
+	}{
+		{nil, 0, 1},
+		{nil, 7, 7},
+		{[]int{1, 2, 3}, 0, 4},
+		{[]int{5, 2}, 0, 6},
+		{[]int{1}, 4, 4}, // Deleted timers keep their IDs
+	}
+	for _, tt := range tests {
+		var timers []*Timer
+		for _, id := range tt.ids {
+			timers = append(timers, &Timer{ID: id})
+		}
+		if got := newID(timers, tt.next); got != tt.want {
+			t.Errorf("newID(%v, %d) = %d, want %d", tt.ids, tt.next, got, tt.want)
+		}
+	}
+}
+
+func TestAddTimerNeverReusesIDs(t *testing.T) {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	m.addTimer(timerSpec{Duration: time.Minute})
+	second := m.addTimer(timerSpec{Duration: time.Minute})
+	m.selectedID = second.ID
+	m.deleteSelected()
+
+	if third := m.addTimer(timerSpec{Duration: time.Minute}); third.ID == second.ID {
+		t.Errorf("new timer reused deleted ID %d", second.ID)
+	}
+}
+
+func TestAddTimerLimit(t *testing.T) {
+	m := initialModel(config{MaxTimers: 2}, defaultStrings, savedState{}, nil)
+	m.addTimer(timerSpec{Duration: time.Minute})
+	m.addTimer(timerSpec{Duration: time.Minute})
+
+	if extra := m.addTimer(timerSpec{Duration: time.Minute}); extra != nil || len(m.timers) != 2 {
+		t.Errorf("have %d timers after adding past max_timers 2", len(m.timers))
+	}
+	if m.toast == "" {
+		t.Error("no message when the timer limit was reached")
+	}
+}
+
+func TestTickAfterSleep(t *testing.T) {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	timer := m.addTimer(timerSpec{Duration: 10 * time.Minute})
+
+	next, _ := m.Update(tickMsg(timer.Deadline.Add(-9 * time.Minute)))
+	m = next.(model)
+
+	// The machine sleeps through the deadline; the next tick is an hour on
+	next, _ = m.Update(tickMsg(timer.Deadline.Add(time.Hour)))
+	m = next.(model)
+	if !timer.Finished || !timer.Alarming {
+		t.Errorf("finished=%v alarming=%v after waking past the deadline, want the alarm at once",
+			timer.Finished, timer.Alarming)
+	}
+	if timer.Remaining != 0 {
+		t.Errorf("remaining = %s, want 0", timer.Remaining)
+	}
+}
+
+// fakeClock replaces m's clock with one that only moves when advanced.
+func fakeClock(m *model) (advance func(time.Duration) tickMsg) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	m.nowFunc = func() time.Time { return now }
+	return func(d time.Duration) tickMsg {
+		now = now.Add(d)
+		return tickMsg(now)
+	}
+}
+
+func TestFakeClockCountsDown(t *testing.T) {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	advance := fakeClock(&m)
+	timer := m.addTimer(timerSpec{Duration: time.Minute})
+
+	next, _ := m.Update(advance(20 * time.Second))
+	m = next.(model)
+	if timer.Remaining != 40*time.Second {
+		t.Errorf("remaining = %s after 20s, want 40s", timer.Remaining)
+	}
+
+	next, _ = m.Update(advance(40 * time.Second))
+	m = next.(model)
+	if !timer.Finished || !timer.Alarming || timer.Remaining != 0 {
+		t.Errorf("finished=%v alarming=%v remaining=%s at the deadline, want the alarm",
+			timer.Finished, timer.Alarming, timer.Remaining)
+	}
+}
+
+func TestFakeClockPauseHoldsRemaining(t *testing.T) {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	advance := fakeClock(&m)
+	timer := m.addTimer(timerSpec{Duration: time.Minute})
+	m.focusIndex = LIST
+	m.selectedID = timer.ID
+
+	next, _ := m.Update(advance(15 * time.Second))
+	m = next.(model)
+	m.toggleSelected()
+	advance(time.Hour)
+	m.toggleSelected()
+
+	next, _ = m.Update(advance(5 * time.Second))
+	m = next.(model)
+	if timer.Remaining != 40*time.Second {
+		t.Errorf("remaining = %s, want 40s: the paused hour should not count", timer.Remaining)
+	}
+}
+
+func TestUndoResetRestoresSequence(t *testing.T) {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	advance := fakeClock(&m)
+	m.addSequence([]timerSpec{{Duration: time.Minute}, {Duration: 2 * time.Minute}})
+	second := m.timers[1]
+
+	m.reset()
+	m.undoReset()
+	next, _ := m.Update(advance(time.Minute))
+	m = next.(model)
+	if second.Queued || !second.Running {
+		t.Errorf("queued=%v running=%v after the first step finished, want the next step started",
+			second.Queued, second.Running)
+	}
+}
+
+func TestSnoozeOnlyOneAlarm(t *testing.T) {
+	m := alarmingModel()
+	second := m.addTimer(timerSpec{Duration: time.Minute})
+	m.finish([]*Timer{second}, m.nowFunc())
+
+	m, _ = press(m, "s")
+	if m.timers[0].Alarming || !second.Alarming {
+		t.Errorf("alarming = %v, %v after s, want only the oldest snoozed",
+			m.timers[0].Alarming, second.Alarming)
+	}
+}
+
+func TestFinishWaitingFreesSlot(t *testing.T) {
+	m := initialModel(config{MaxRunning: 1}, defaultStrings, savedState{}, nil)
+	advance := fakeClock(&m)
+	a := m.addTimer(timerSpec{Duration: time.Minute})
+	b := m.addTimer(timerSpec{Duration: time.Minute})
+	c := m.addTimer(timerSpec{Duration: time.Minute})
+
+	m.finish([]*Timer{b}, m.nowFunc()) // f on a waiting timer
+	if b.Waiting {
+		t.Error("finished timer is still waiting")
+	}
+	next, _ := m.Update(advance(time.Minute))
+	m = next.(model)
+	if !a.Finished || b.Running || !c.Running {
+		t.Errorf("a finished=%v, b running=%v, c running=%v; want c to take the free slot",
+			a.Finished, b.Running, c.Running)
+	}
+}
+
+func TestRepeatRecordsRunStart(t *testing.T) {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	advance := fakeClock(&m)
+	timer := m.addTimer(timerSpec{Duration: time.Minute, Repeat: 1})
+
+	next, _ := m.Update(advance(time.Minute))
+	m = next.(model)
+	second := m.nowFunc()
+	entry := newHistoryEntry(timer, second.Add(time.Minute), m.session)
+	if !entry.Started.Equal(second) {
+		t.Errorf("second run started %s, want %s", entry.Started, second)
+	}
+}
+
+func TestDuplicateWarningWithLabelTemplate(t *testing.T) {
+	m := initialModel(config{WarnDuplicates: true, LabelTemplate: "Task #{n}"}, defaultStrings, savedState{}, nil)
+	for range 2 {
+		m.textInput.SetValue("5m")
+		m.submitInput()
+	}
+	if len(m.timers) != 1 || m.confirming != CONFIRM_DUPLICATE {
+		t.Errorf("%d timers, confirming %v; want the second 5m to ask first", len(m.timers), m.confirming)
+	}
+}
+
+func TestCompactNeverFocusesButtons(t *testing.T) {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	m.compact = true
+	timer := m.addTimer(timerSpec{Duration: time.Minute})
+	m.focusIndex = LIST
+	m.selectedID = timer.ID
+
+	m, _ = press(m, "M")
+	if m.focusIndex != INPUT {
+		t.Errorf("focus = %d after M in compact view, want the input", m.focusIndex)
+	}
+	m.minimized = false
+	m.focusIndex = LIST
+	m.deleteSelected()
+	if m.focusIndex != INPUT {
+		t.Errorf("focus = %d after deleting the last timer, want the input", m.focusIndex)
+	}
+}
+
+func TestDismissOldestAlarmFirst(t *testing.T) {
+	m := initialModel(config{}, defaultStrings, savedState{}, nil)
+	advance := fakeClock(&m)
+	a := m.addTimer(timerSpec{Duration: time.Hour})
+	b := m.addTimer(timerSpec{Duration: time.Hour})
+	m.finish([]*Timer{b}, m.nowFunc())
+	advance(time.Second)
+	m.finish([]*Timer{a}, m.nowFunc())
+
+	m, _ = press(m, "x")
+	if b.Alarming || !a.Alarming {
+		t.Errorf("alarming a=%v b=%v, want b, which rang first, dismissed", a.Alarming, b.Alarming)
+	}
+}
+
+func TestReplayDoesNotAnnounceAgain(t *testing.T) {
+	m := initialModel(config{BatchSound: "count"}, defaultStrings, savedState{}, nil)
+	a := m.addTimer(timerSpec{Duration: time.Minute})
+	b := m.addTimer(timerSpec{Duration: time.Minute})
+	m.finish([]*Timer{a, b}, m.nowFunc())
+	m.toast = ""
+
+	next, _ := m.Update(alarmRepeatMsg(m.nowFunc()))
+	if toast := next.(model).toast; toast != "" {
+		t.Errorf("replay showed %q again", toast)
+	}
+}
+
+func TestSoundFailureShowsToast(t *testing.T) {
+	m := alarmingModel()
+	res := soundResult{Player: "bell", Err: errSoundUnavailable}
+
+	next, _ := m.Update(soundResultMsg(res))
+	m = next.(model)
+	if m.lastSound.Err != errSoundUnavailable {
+		t.Errorf("lastSound = %+v, want the failed result kept", m.lastSound)
+	}
+	if want := "Alarm sound failed (bell): " + errSoundUnavailable.Error(); m.toast != want {
+		t.Errorf("toast = %q, want %q", m.toast, want)
+	}
+
+	// A sound stopped by a key press is not a failure
+	m.toast = ""
+	res.Canceled = true
+	next, _ = m.Update(soundResultMsg(res))
+	if toast := next.(model).toast; toast != "" {
+		t.Errorf("canceled sound showed %q", toast)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// buttonLabels returns the text of every button in the row, by focus.
+func (m model) buttonLabels() map[Focus]string {
+	labels := map[Focus]string{
+		ADD:   m.text.Add,
+		START: m.text.Start,
+		STOP:  m.text.Stop,
+		RESET: m.text.Reset,
+		CLEAR: m.text.Clear,
+		QUIT:  m.text.Quit,
+	}
+	for i, b := range m.cfg.Buttons {
+		labels[QUIT+Focus(i+1)] = b.title()
+	}
+	return labels
+}
+
+// buttonAt finds the button drawn at screen cell (x, y) by locating each
+// "[ label ]" in the rendered view, so it follows centering and layout.
+func (m model) buttonAt(x, y int) (Focus, bool) {
+	lines := strings.Split(m.View(), "\n")
+	if y < 0 || y >= len(lines) {
+		return 0, false
+	}
+	line := ansi.Strip(lines[y])
+	for f, label := range m.buttonLabels() {
+		i := strings.Index(line, "[ "+label+" ]")
+		if i < 0 {
+			continue
+		}
+		start := ansi.StringWidth(line[:i])
+		if x >= start && x < start+ansi.StringWidth("[ "+label+" ]") {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// click focuses the button under a left click and presses it.
+func (m model) click(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return m, nil
+	}
+	f, ok := m.buttonAt(msg.X, msg.Y)
+	if !ok {
+		return m, nil
+	}
+	m.focusIndex = f
+	m.focusState = f
+	m.textInput.Blur()
+	if m.dismissAlarms() || m.confirming != CONFIRM_NONE {
+		return m, nil
+	}
+	return m.press(f)
+}
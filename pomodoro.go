@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Pomodoro phases. Every pomodoroCycles work phases the break is a long one.
+const (
+	pomodoroWork      = 25 * time.Minute
+	pomodoroBreak     = 5 * time.Minute
+	pomodoroLongBreak = 15 * time.Minute
+	pomodoroCycles    = 4
+)
+
+// pomodoroPhase names t's current phase, e.g. "Work 1/4" or "Break".
+func pomodoroPhase(t *Timer) string {
+	switch {
+	case !t.OnBreak:
+		return fmt.Sprintf("Work %d/%d", t.Cycle, pomodoroCycles)
+	case t.Cycle == pomodoroCycles:
+		return "Long break"
+	}
+	return "Break"
+}
+
+// nextPhase starts the pomodoro phase after the one t just finished. The
+// alarm keeps ringing until dismissed while the new phase runs.
+func (m *model) nextPhase(t *Timer, now time.Time) {
+	if t.OnBreak {
+		t.OnBreak = false
+		t.Cycle = t.Cycle%pomodoroCycles + 1
+		t.Duration = pomodoroWork
+	} else {
+		t.OnBreak = true
+		t.Duration = pomodoroBreak
+		if t.Cycle == pomodoroCycles {
+			t.Duration = pomodoroLongBreak
+		}
+	}
+	t.Label = pomodoroPhase(t)
+	t.Remaining = t.Duration
+	t.Finished = false
+	t.RunStart = now
+	m.startTimer(t)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// sessionSnapshot is a saved setup of timers, for comparing routines.
+type sessionSnapshot struct {
+	Saved  time.Time      `json:"saved"`
+	Timers []sessionTimer `json:"timers"`
+}
+
+type sessionTimer struct {
+	Label    string `json:"label,omitempty"`
+	Duration string `json:"duration"`
+}
+
+func sessionsDir() string {
+	return filepath.Join(stateDir(), "sessions")
+}
+
+// saveSession writes the current timers to sessions/<timestamp>.json and
+// returns the snapshot's name.
+func saveSession(timers []*Timer, now time.Time) (string, error) {
+	snap := sessionSnapshot{Saved: now}
+	for _, t := range timers {
+		snap.Timers = append(snap.Timers, sessionTimer{Label: t.Label, Duration: t.Duration.String()})
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(sessionsDir(), 0o755); err != nil {
+		return "", err
+	}
+	name := now.Format("20060102-150405")
+	return name, os.WriteFile(filepath.Join(sessionsDir(), name+".json"), data, 0o644)
+}
+
+// loadSession reads a snapshot by path, or by name from the sessions dir.
+func loadSession(name string) (sessionSnapshot, error) {
+	var snap sessionSnapshot
+	path := name
+	if _, err := os.Stat(path); err != nil {
+		path = filepath.Join(sessionsDir(), strings.TrimSuffix(name, ".json")+".json")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return snap, err
+	}
+	return snap, json.Unmarshal(data, &snap)
+}
+
+// diffSessions prints the two snapshots side by side, position by position:
+// "  " unchanged, "~ " different duration or label, "- " only in a,
+// "+ " only in b.
+func diffSessions(w io.Writer, a, b sessionSnapshot) {
+	describe := func(t sessionTimer) string {
+		if t.Label == "" {
+			return t.Duration
+		}
+		return t.Duration + " " + t.Label
+	}
+
+	for i := range max(len(a.Timers), len(b.Timers)) {
+		switch {
+		case i >= len(b.Timers):
+			fmt.Fprintf(w, "- %d: %s\n", i+1, describe(a.Timers[i]))
+		case i >= len(a.Timers):
+			fmt.Fprintf(w, "+ %d: %s\n", i+1, describe(b.Timers[i]))
+		case a.Timers[i] != b.Timers[i]:
+			fmt.Fprintf(w, "~ %d: %s -> %s\n", i+1, describe(a.Timers[i]), describe(b.Timers[i]))
+		default:
+			fmt.Fprintf(w, "  %d: %s\n", i+1, describe(a.Timers[i]))
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package main
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// watchDismissSignal is a no-op where SIGUSR1 does not exist.
+func watchDismissSignal(p *tea.Program) {}
+
+// watchHangup is a no-op where SIGHUP does not exist.
+func watchHangup(p *tea.Program) {}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchDismissSignal dismisses alarms whenever the process receives SIGUSR1,
+// so a notification action or hotkey can silence the timer.
+func watchDismissSignal(p *tea.Program) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGUSR1)
+	go func() {
+		for range c {
+			p.Send(dismissAlarmMsg{})
+		}
+	}()
+}
+
+// watchHangup quits cleanly when the terminal closes (SIGHUP), so open
+// timers are still saved.
+func watchHangup(p *tea.Program) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	go func() {
+		<-c
+		p.Quit()
+	}()
+}
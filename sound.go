@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// soundResult describes how an alarm sound attempt resolved.
+type soundResult struct {
+	Player   string // "paplay", "pw-play", "aplay", "afplay", "powershell" or "bell"
+	File     string
+	Err      error
+	Canceled bool // Stopped by a key press before it finished
+
+	AnnounceErr error // From the spoken announcement, if one ran
+}
+
+// soundResultMsg is returned by the alarm command once playback ends.
+type soundResultMsg soundResult
+
+// soundEnv names an alarm sound file that is tried before the defaults. It
+// overrides sound_file in the config.
+const soundEnv = "TUI_TIMER_SOUND"
+
+// configuredSound is the user's alarm sound, or "" for the platform default.
+func configuredSound(soundFile string) string {
+	if sf := os.Getenv(soundEnv); sf != "" {
+		return sf
+	}
+	return soundFile
+}
+
+// soundMissing reports whether a configured sound file does not exist.
+func soundMissing(sf string) bool {
+	if sf == "" {
+		return false
+	}
+	_, err := os.Stat(sf)
+	return err != nil
+}
+
+// systemSounds are the platform's stock alarm sounds, best first.
+func systemSounds() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{
+			"/System/Library/Sounds/Glass.aiff",
+			"/System/Library/Sounds/Ping.aiff",
+		}
+	case "windows":
+		return []string{
+			`C:\Windows\Media\Alarm01.wav`,
+			`C:\Windows\Media\notify.wav`,
+		}
+	}
+	return []string{
+		"/usr/share/sounds/freedesktop/stereo/alarm-clock-elapsed.oga",
+		"/usr/share/sounds/freedesktop/stereo/complete.oga",
+	}
+}
+
+// soundPlayers are the commands that can play file at volume percent, best
+// first. Windows' SoundPlayer and aplay have no volume setting.
+func soundPlayers(file string, volume int) [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"afplay", "-v", fmt.Sprintf("%.2f", float64(volume)/100), file}}
+	case "windows":
+		script := fmt.Sprintf("(New-Object Media.SoundPlayer '%s').PlaySync()", strings.ReplaceAll(file, "'", "''"))
+		return [][]string{{"powershell", "-NoProfile", "-Command", script}}
+	}
+	return [][]string{
+		{"paplay", fmt.Sprintf("--volume=%d", volume*65536/100), file}, // 65536 is normal volume
+		{"pw-play", fmt.Sprintf("--volume=%.2f", float64(volume)/100), file},
+		{"aplay", "-q", file},
+	}
+}
+
+// errSoundUnavailable means every sound file and player failed, leaving
+// only the terminal bell, which many terminals silence.
+var errSoundUnavailable = errors.New("sound unavailable, rang the terminal bell")
+
+// soundLogEnv names a file that every sound attempt is appended to, for
+// finding out why an alarm is silent.
+const soundLogEnv = "TUI_TIMER_SOUND_LOG"
+
+// playSound works down a chain until something plays: soundFile, then the
+// system sounds, each with every player that is installed, then the
+// Windows console beep, then the terminal bell.
+func playSound(ctx context.Context, soundFile string, volume int) soundResult {
+	soundFiles := systemSounds()
+	if soundFile != "" {
+		soundFiles = append([]string{soundFile}, soundFiles...)
+	}
+
+	var attempts []string
+	defer func() { logSoundAttempts(attempts) }()
+
+	for _, sf := range soundFiles {
+		if _, err := os.Stat(sf); err != nil {
+			attempts = append(attempts, err.Error())
+			continue
+		}
+		for _, args := range soundPlayers(sf, volume) {
+			if _, err := exec.LookPath(args[0]); err != nil {
+				attempts = append(attempts, fmt.Sprintf("%s: not installed", args[0]))
+				continue
+			}
+			// Run with context so we can kill it
+			err := exec.CommandContext(ctx, args[0], args[1:]...).Run()
+			res := soundResult{Player: args[0], File: sf, Err: err, Canceled: ctx.Err() != nil}
+			attempts = append(attempts, fmt.Sprintf("%s %s: %v", args[0], sf, resultText(err, res.Canceled)))
+			if err == nil || res.Canceled {
+				res.Err = nil
+				return res
+			}
+		}
+	}
+	if runtime.GOOS == "windows" {
+		// The console beep works without any sound files
+		err := exec.CommandContext(ctx, "powershell", "-NoProfile", "-Command", "[console]::beep(880,500)").Run()
+		attempts = append(attempts, fmt.Sprintf("console beep: %v", resultText(err, ctx.Err() != nil)))
+		if err == nil || ctx.Err() != nil {
+			return soundResult{Player: "powershell", Canceled: ctx.Err() != nil}
+		}
+	}
+	// Fallback to bell
+	fmt.Print("\a")
+	attempts = append(attempts, "bell")
+	return soundResult{Player: "bell", Err: errSoundUnavailable}
+}
+
+func resultText(err error, canceled bool) string {
+	switch {
+	case canceled:
+		return "stopped"
+	case err != nil:
+		return err.Error()
+	}
+	return "ok"
+}
+
+// logSoundAttempts appends one alarm's attempts to the TUI_TIMER_SOUND_LOG
+// file, if it is set. Failures to write are ignored.
+func logSoundAttempts(attempts []string) {
+	path := os.Getenv(soundLogEnv)
+	if path == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	now := time.Now().Format(time.RFC3339)
+	for _, a := range attempts {
+		fmt.Fprintf(f, "%s %s\n", now, a)
+	}
+}
+
+// announce speaks text with a TTS command such as "espeak" or "say". The
+// text is passed as the final argument.
+func announce(ctx context.Context, command, text string) error {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return errors.New("empty announce command")
+	}
+	args = append(args, text)
+	return exec.CommandContext(ctx, args[0], args[1:]...).Run()
+}
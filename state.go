@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// savedState is state.json: the open timers and recent durations, written
+// on quit and restored on the next start.
+type savedState struct {
+	SavedAt time.Time       `json:"saved_at"`
+	NextID  int             `json:"next_id"`
+	Timers  []savedTimer    `json:"timers"`
+	Recent  []time.Duration `json:"recent,omitempty"`
+
+	// The running sequence, by timer ID: its current step and the steps
+	// still queued, in order
+	SeqHead  int   `json:"seq_head,omitempty"`
+	Sequence []int `json:"sequence,omitempty"`
+}
+
+type savedTimer struct {
+	ID            int           `json:"id"`
+	Label         string        `json:"label,omitempty"`
+	Duration      time.Duration `json:"duration"`
+	Remaining     time.Duration `json:"remaining"`
+	Running       bool          `json:"running"`
+	Finished      bool          `json:"finished"`
+	Priority      Priority      `json:"priority,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	PauseAt       time.Duration `json:"pause_at,omitempty"`
+	CheckpointHit bool          `json:"checkpoint_hit,omitempty"`
+	Command       string        `json:"command,omitempty"`
+	CountUp       bool          `json:"count_up,omitempty"`
+	Elapsed       time.Duration `json:"elapsed,omitempty"`
+	Pomodoro      bool          `json:"pomodoro,omitempty"`
+	Cycle         int           `json:"cycle,omitempty"`
+	OnBreak       bool          `json:"on_break,omitempty"`
+	RunStart      time.Time     `json:"run_start"`
+	SoundFile     string        `json:"sound_file,omitempty"`
+	Repeat        int           `json:"repeat,omitempty"`
+	Group         string        `json:"group,omitempty"`
+	Waiting       bool          `json:"waiting,omitempty"`
+	Queued        bool          `json:"queued,omitempty"`
+}
+
+func statePath() string {
+	return filepath.Join(stateDir(), "state.json")
+}
+
+func newSavedState(m model, now time.Time) savedState {
+	s := savedState{SavedAt: now, NextID: m.nextID, Recent: m.recent}
+	if m.seqHead != nil {
+		s.SeqHead = m.seqHead.ID
+	}
+	for _, t := range m.sequence {
+		s.Sequence = append(s.Sequence, t.ID)
+	}
+	for _, t := range m.timers {
+		remaining, elapsed := t.Remaining, t.Elapsed
+		if t.Running && t.CountUp {
+			elapsed = now.Sub(t.StartedAt)
+		} else if t.Running {
+			remaining = max(0, t.Deadline.Sub(now))
+		}
+		s.Timers = append(s.Timers, savedTimer{
+			ID:            t.ID,
+			Label:         t.Label,
+			Duration:      t.Duration,
+			Remaining:     remaining,
+			Running:       t.Running,
+			Finished:      t.Finished,
+			Priority:      t.Priority,
+			CreatedAt:     t.CreatedAt,
+			PauseAt:       t.PauseAt,
+			CheckpointHit: t.CheckpointHit,
+			Command:       t.Command,
+			CountUp:       t.CountUp,
+			Elapsed:       elapsed,
+			Pomodoro:      t.Pomodoro,
+			Cycle:         t.Cycle,
+			OnBreak:       t.OnBreak,
+			RunStart:      t.RunStart,
+			SoundFile:     t.SoundFile,
+			Repeat:        t.Repeat,
+			Group:         t.Group,
+			Waiting:       t.Waiting,
+			Queued:        t.Queued,
+		})
+	}
+	return s
+}
+
+func saveState(path string, s savedState) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func loadState(path string) (savedState, error) {
+	var s savedState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return savedState{}, err
+	}
+	return s, nil
+}
+
+// restoreTimers rebuilds the saved timers as of now. Timers that were
+// running resume, less the time the app was closed; ones whose time ran
+// out meanwhile come back finished, without an alarm. Paused timers are
+// restored exactly as they were.
+func restoreTimers(s savedState, now time.Time) []*Timer {
+	elapsed := max(0, now.Sub(s.SavedAt))
+	var timers []*Timer
+	for _, st := range s.Timers {
+		t := &Timer{
+			ID:            st.ID,
+			Label:         st.Label,
+			Duration:      st.Duration,
+			Remaining:     st.Remaining,
+			Running:       st.Running,
+			Finished:      st.Finished,
+			Priority:      st.Priority,
+			CreatedAt:     st.CreatedAt,
+			PauseAt:       st.PauseAt,
+			CheckpointHit: st.CheckpointHit,
+			Command:       st.Command,
+			CountUp:       st.CountUp,
+			Elapsed:       st.Elapsed,
+			Pomodoro:      st.Pomodoro,
+			Cycle:         st.Cycle,
+			OnBreak:       st.OnBreak,
+			RunStart:      st.RunStart,
+			SoundFile:     st.SoundFile,
+			Repeat:        st.Repeat,
+			Group:         st.Group,
+			Waiting:       st.Waiting,
+			Queued:        st.Queued,
+		}
+		if t.Running && t.CountUp {
+			// A stopwatch keeps counting while the app is closed
+			t.Elapsed += elapsed
+			t.StartedAt = now.Add(-t.Elapsed)
+		} else if t.Running && !t.Finished {
+			t.Remaining -= elapsed
+			if t.Remaining <= 0 {
+				t.Remaining = 0
+				t.Running = false
+				t.Finished = true
+			}
+			t.Deadline = now.Add(t.Remaining)
+		}
+		timers = append(timers, t)
+	}
+	return timers
+}
+
+// restoreState replaces the model's timers with the saved ones.
+func (m *model) restoreState(s savedState, now time.Time) {
+	m.timers = restoreTimers(s, now)
+	m.nextID = max(m.nextID, s.NextID)
+	missed := 0
+	for i, t := range m.timers {
+		if t.Finished && !s.Timers[i].Finished {
+			missed++
+		}
+	}
+	if missed > 0 {
+		m.setToast(fmt.Sprintf(m.text.Toast.FinishedClosed, missed))
+	}
+
+	byID := func(id int) *Timer {
+		i := slices.IndexFunc(m.timers, func(t *Timer) bool { return t.ID == id })
+		if i < 0 {
+			return nil
+		}
+		return m.timers[i]
+	}
+	m.seqHead = byID(s.SeqHead)
+	for _, id := range s.Sequence {
+		if t := byID(id); t != nil && t.Queued {
+			m.sequence = append(m.sequence, t)
+		}
+	}
+	for _, t := range m.timers {
+		if t.Queued && !slices.Contains(m.sequence, t) {
+			t.Queued = false // From an older file without the sequence; left paused
+		}
+	}
+	if m.seqHead == nil || m.seqHead.Finished {
+		m.advanceSequence() // The step ran out while closed
+	}
+	m.startWaiting() // Slots freed by timers that finished while closed
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRestoreTimers(t *testing.T) {
+	savedAt := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	s := savedState{
+		SavedAt: savedAt,
+		NextID:  5,
+		Timers: []savedTimer{
+			{ID: 1, Duration: 10 * time.Minute, Remaining: 5 * time.Minute, Running: true},
+			{ID: 2, Duration: 10 * time.Minute, Remaining: 5 * time.Minute},
+			{ID: 3, Duration: 10 * time.Minute, Remaining: time.Minute, Running: true},
+			{ID: 4, Duration: time.Minute, Finished: true},
+		},
+	}
+
+	timers := restoreTimers(s, savedAt.Add(2*time.Minute))
+	if len(timers) != 4 {
+		t.Fatalf("restored %d timers, want 4", len(timers))
+	}
+
+	tests := []struct {
+		name      string
+		remaining time.Duration
+		running   bool
+		finished  bool
+	}{
+		{"running resumes less the time closed", 3 * time.Minute, true, false},
+		{"paused stays paused", 5 * time.Minute, false, false},
+		{"running that elapsed comes back finished", 0, false, true},
+		{"finished stays finished", 0, false, true},
+	}
+	for i, tt := range tests {
+		got := timers[i]
+		if got.Remaining != tt.remaining || got.Running != tt.running || got.Finished != tt.finished {
+			t.Errorf("%s: got remaining %s running %v finished %v", tt.name, got.Remaining, got.Running, got.Finished)
+		}
+		if got.Alarming {
+			t.Errorf("%s: restored timer is alarming", tt.name)
+		}
+	}
+}
+
+func TestRestoreStateKeepsTicking(t *testing.T) {
+	now := time.Now()
+	s := savedState{
+		SavedAt: now,
+		NextID:  2,
+		Timers:  []savedTimer{{ID: 1, Duration: time.Minute, Remaining: time.Minute, Running: true}},
+	}
+	m := initialModel(config{}, defaultStrings, s, []time.Duration{30 * time.Second})
+	if len(m.timers) != 2 || m.timers[1].ID != 2 {
+		t.Fatalf("command-line timer not added after the restored one: %+v", m.timers)
+	}
+
+	next, _ := m.Update(tickMsg(m.timers[0].Deadline.Add(-30 * time.Second)))
+	m = next.(model)
+	if m.timers[0].Remaining != 30*time.Second {
+		t.Errorf("restored timer did not tick, %s remaining", m.timers[0].Remaining)
+	}
+}
+
+func TestLoadCorruptState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	data := `{"timers": [{"id": 1, "duration": 60000000000, "running": true}], "saved_at": "yesterday"}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := loadState(path)
+	if err == nil {
+		t.Fatal("no error for a bad saved_at")
+	}
+	if len(s.Timers) != 0 {
+		t.Errorf("restored %d timers from a corrupt file, want none", len(s.Timers))
+	}
+}
+
+func TestRestoreStateKeepsSequenceAndWaiting(t *testing.T) {
+	m := initialModel(config{MaxRunning: 1}, defaultStrings, savedState{}, nil)
+	m.addTimer(timerSpec{Duration: time.Hour})
+	waiting := m.addTimer(timerSpec{Duration: time.Hour})
+	m.cfg.MaxRunning = 0
+	m.addSequence([]timerSpec{{Duration: time.Minute}, {Duration: time.Minute}})
+	s := newSavedState(m, time.Now())
+
+	restored := initialModel(config{MaxRunning: 1}, defaultStrings, s, nil)
+	if got := restored.timers[1]; got.ID != waiting.ID || !got.Waiting {
+		t.Errorf("timer #%d waiting=%v after restore, want still waiting", got.ID, got.Waiting)
+	}
+	if restored.seqHead == nil || restored.seqHead.ID != m.seqHead.ID || len(restored.sequence) != 1 {
+		t.Errorf("sequence not restored: head %v, %d queued", restored.seqHead, len(restored.sequence))
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// styles holds every style used by View, grouped by screen region so each
+// region can be restyled independently from the config file.
+type styles struct {
+	Header  lipgloss.Style // Recent-duration chips above the list
+	Input   lipgloss.Style // "New Timer:" label and typed text
+	List    lipgloss.Style // Unselected timer rows
+	Buttons lipgloss.Style // Unfocused buttons
+	Help    lipgloss.Style // Help line at the bottom
+
+	Detail lipgloss.Style // Bordered detail pane beside the list
+
+	Focused lipgloss.Style // Focused button and selected row
+	Muted   lipgloss.Style // Secondary details (start time, checkpoints, empty list)
+	Error   lipgloss.Style // Rejected input, under the field
+
+	// Animation styles
+	Alarm      lipgloss.Style
+	AlarmSolid lipgloss.Style // Used instead of blinking
+	Flash      lipgloss.Style // The whole screen, with flash_screen
+
+	// Remaining time on a countdown, by how soon it ends
+	Plenty   lipgloss.Style
+	Soon     lipgloss.Style
+	Imminent lipgloss.Style
+
+	// Priority markers
+	HighPriority lipgloss.Style
+	LowPriority  lipgloss.Style
+
+	focusedButton string // Format strings for a button label
+	blurredButton string
+}
+
+// styleSpec is one style as written in the config file. Empty colors keep
+// the region's default.
+type styleSpec struct {
+	Foreground string `json:"foreground"`
+	Background string `json:"background"`
+	Bold       bool   `json:"bold"`
+	Italic     bool   `json:"italic"`
+	Underline  bool   `json:"underline"`
+	Faint      bool   `json:"faint"`
+}
+
+// styleConfig is the "styles" section of the config file.
+type styleConfig struct {
+	Header  *styleSpec `json:"header"`
+	Input   *styleSpec `json:"input"`
+	List    *styleSpec `json:"list"`
+	Buttons *styleSpec `json:"buttons"`
+	Help    *styleSpec `json:"help"`
+	Focused *styleSpec `json:"focused"`
+	Alarm   *styleSpec `json:"alarm"`
+}
+
+func (s *styleSpec) apply(base lipgloss.Style) lipgloss.Style {
+	if s == nil {
+		return base
+	}
+	if s.Foreground != "" {
+		base = base.Foreground(lipgloss.Color(s.Foreground))
+	}
+	if s.Background != "" {
+		base = base.Background(lipgloss.Color(s.Background))
+	}
+	if s.Bold {
+		base = base.Bold(true)
+	}
+	if s.Italic {
+		base = base.Italic(true)
+	}
+	if s.Underline {
+		base = base.Underline(true)
+	}
+	if s.Faint {
+		base = base.Faint(true)
+	}
+	return base
+}
+
+// theme is the palette every style is built from.
+type theme struct {
+	Focused  lipgloss.Color
+	Blurred  lipgloss.Color
+	Alarm    lipgloss.Color
+	Plenty   lipgloss.Color
+	Soon     lipgloss.Color
+	Imminent lipgloss.Color
+	Flash    lipgloss.Color // Text on the red flash_screen background
+}
+
+var themes = map[string]theme{
+	"dark": {
+		Focused:  "205",
+		Blurred:  "240",
+		Alarm:    "196",
+		Plenty:   "42",
+		Soon:     "220",
+		Imminent: "196",
+		Flash:    "231",
+	},
+	"light": {
+		Focused:  "162",
+		Blurred:  "245",
+		Alarm:    "160",
+		Plenty:   "28",
+		Soon:     "136",
+		Imminent: "160",
+		Flash:    "231",
+	},
+}
+
+// defaultTheme suits the terminal's background.
+func defaultTheme() string {
+	if lipgloss.HasDarkBackground() {
+		return "dark"
+	}
+	return "light"
+}
+
+func newStyles(th theme, cfg styleConfig) styles {
+	focused := lipgloss.NewStyle().Foreground(th.Focused)
+	blurred := lipgloss.NewStyle().Foreground(th.Blurred)
+	alarm := lipgloss.NewStyle().Foreground(th.Alarm).Bold(true)
+
+	st := styles{
+		Header:  cfg.Header.apply(blurred),
+		Input:   cfg.Input.apply(lipgloss.NewStyle()),
+		List:    cfg.List.apply(lipgloss.NewStyle()),
+		Buttons: cfg.Buttons.apply(blurred),
+		Help:    cfg.Help.apply(blurred),
+		Detail: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(th.Blurred).
+			Padding(0, 1),
+
+		Focused: cfg.Focused.apply(focused),
+		Muted:   blurred,
+		Error:   lipgloss.NewStyle().Foreground(th.Alarm),
+
+		Alarm: cfg.Alarm.apply(alarm),
+
+		Plenty:   lipgloss.NewStyle().Foreground(th.Plenty),
+		Soon:     lipgloss.NewStyle().Foreground(th.Soon),
+		Imminent: lipgloss.NewStyle().Foreground(th.Imminent),
+
+		HighPriority: lipgloss.NewStyle().Foreground(th.Alarm),
+		LowPriority:  blurred,
+	}
+	st.AlarmSolid = st.Alarm.Reverse(true)
+	st.Flash = lipgloss.NewStyle().Foreground(th.Flash).Background(th.Alarm)
+	st.focusedButton = st.Focused.Render("[ %s ]")
+	st.blurredButton = fmt.Sprintf("[ %s ]", st.Buttons.Render("%s"))
+	return st
+}
@@ -0,0 +1,702 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// View stacks the screen sections. The default layout reads top-down with
+// the input first; layout "bottom" moves the input and buttons under the
+// list and anchors everything to the bottom of the window, like a chat.
+func (m model) View() string {
+	if m.compact {
+		return m.viewCompact()
+	}
+	if m.tooSmall() {
+		return m.viewTooSmall()
+	}
+	if t := m.zoomedTimer(); t != nil && !m.inline {
+		return m.flash(m.viewZoom(t))
+	}
+
+	var sections []string
+	add := func(section string) {
+		if section != "" {
+			sections = append(sections, section)
+		}
+	}
+
+	alarms, progress, reference, input := m.viewAlarms(), m.viewProgress(), m.viewReference(), m.viewInput()
+	totals, buttons, footer := m.viewTotals(), m.viewButtons(), m.viewFooter()
+
+	// The list gets whatever height the other sections and the blank
+	// lines between them leave over
+	used := 0
+	for _, s := range []string{alarms, progress, reference, input, totals, buttons, footer} {
+		if s != "" {
+			used += lipgloss.Height(s) + 1
+		}
+	}
+	maxLines := 0
+	if m.height > 0 {
+		maxLines = max(1, m.height-used)
+	}
+	list := m.viewList(maxLines)
+
+	add(alarms)
+	add(progress)
+	add(reference)
+	if m.cfg.Layout == "bottom" {
+		add(list)
+		add(input)
+	} else {
+		add(input)
+		add(list)
+	}
+	add(totals)
+	add(buttons)
+	add(footer)
+
+	content := strings.Join(sections, "\n\n")
+	if m.cfg.rtl() {
+		content = lipgloss.NewStyle().Align(lipgloss.Right).Render(content)
+	}
+	if m.inline {
+		// Stay in the scrollback: no centering and no blank spacer lines
+		return strings.ReplaceAll(content, "\n\n", "\n")
+	}
+	vertical := lipgloss.Center
+	if m.cfg.Layout == "bottom" {
+		vertical = lipgloss.Bottom
+	}
+	return m.flash(lipgloss.Place(m.width, m.height, lipgloss.Center, vertical, content))
+}
+
+// flash turns the whole screen red on every other blink while an alarm
+// rings, if flash_screen is set.
+func (m model) flash(screen string) string {
+	if !m.cfg.FlashScreen || m.cfg.noBlink() || !m.blink || !m.anyAlarming() {
+		return screen
+	}
+	// Inner styles would reset the background part way along each line
+	return m.styles.Flash.Render(ansi.Strip(screen))
+}
+
+// viewProgress is the overall progress bar.
+func (m model) viewProgress() string {
+	if len(m.timers) == 0 {
+		return ""
+	}
+	return m.overallBar.ViewAs(m.overallProgress())
+}
+
+// viewReference is the T-0 header line.
+func (m model) viewReference() string {
+	if m.referenceTime.IsZero() {
+		return ""
+	}
+	return m.styles.Header.Render(fmt.Sprintf("T-0 %s (now %s)", m.referenceTime.Format("15:04:05"), relativeToReference(m.nowFunc(), m.referenceTime)))
+}
+
+// viewInput is the input line and the recent-duration chips below it.
+func (m model) viewInput() string {
+	var s strings.Builder
+	rtl := m.cfg.rtl()
+
+	label := m.text.NewTimer
+	if m.editingID != 0 {
+		label = fmt.Sprintf("%s #%d: ", m.text.Edit, m.editingID)
+	}
+	if rtl {
+		s.WriteString(m.textInput.View())
+		s.WriteString(" " + m.styles.Input.Render(reverseLabel(label)))
+	} else {
+		s.WriteString(m.styles.Input.Render(label))
+		s.WriteString(m.textInput.View())
+	}
+	if m.inputError != "" {
+		s.WriteString("\n")
+		s.WriteString(m.styles.Error.Render("✗ " + m.inputError))
+	}
+	if len(m.recent) > 0 {
+		chips := make([]string, len(m.recent))
+		for i, d := range m.recent {
+			chips[i] = fmt.Sprintf("[F%d %s]", i+1, d)
+		}
+		if rtl {
+			slices.Reverse(chips)
+		}
+		s.WriteString("\n")
+		s.WriteString(m.styles.Header.Render(strings.Join(chips, " ")))
+	}
+	return s.String()
+}
+
+// viewList is the timer list, its minimized summary, the heatmap, or the
+// empty message. The list takes at most maxLines lines, scrolled to keep
+// the selected timer visible; maxLines <= 0 means the window size is not
+// known yet, so nothing is cut.
+func (m model) viewList(maxLines int) string {
+	if m.showHeatmap {
+		return renderHeatmap(m.finishTimes, m.nowFunc(), m.styles, m.text.NoFinishes)
+	}
+	if len(m.timers) == 0 {
+		return m.styles.Muted.Render(m.text.NoTimers)
+	}
+	if m.minimized {
+		summary := m.summary()
+		if m.anyAlarming() && m.cfg.noBlink() {
+			summary = m.styles.AlarmSolid.Render(summary)
+		} else if m.anyAlarming() && m.blink {
+			summary = m.styles.Alarm.Render(summary)
+		}
+		return summary
+	}
+
+	rtl := m.cfg.rtl()
+	var rows []string
+	selectedRow := -1
+	section := Section(-1)
+	groups := m.groupNames()
+	group := -1
+	for _, t := range m.displayTimers() {
+		if m.byGroup && (group < 0 || t.Group != groups[group]) {
+			// Headers for any collapsed groups in between come first
+			for group++; groups[group] != t.Group; group++ {
+				rows = append(rows, m.groupHeader(groups[group]))
+			}
+			rows = append(rows, m.groupHeader(t.Group))
+		}
+		if m.grouped && t.section() != section {
+			// Headers for any collapsed sections in between come first
+			for section++; section <= t.section(); section++ {
+				rows = append(rows, m.sectionHeader(section))
+			}
+			section = t.section()
+		}
+		selected := m.focusIndex == LIST && t.ID == m.selectedID
+		if selected {
+			selectedRow = len(rows)
+		}
+		switch {
+		case selected && rtl:
+			rows = append(rows, m.styles.Focused.Render(m.renderTimer(t)+" <"))
+		case selected:
+			rows = append(rows, m.styles.Focused.Render("> "+m.renderTimer(t)))
+		case rtl:
+			rows = append(rows, m.styles.List.Render(m.renderTimer(t)+"  "))
+		default:
+			rows = append(rows, m.styles.List.Render("  "+m.renderTimer(t)))
+		}
+	}
+	if m.grouped {
+		for section++; section <= SECTION_FINISHED; section++ {
+			rows = append(rows, m.sectionHeader(section))
+		}
+	}
+	if m.byGroup {
+		for group++; group < len(groups); group++ {
+			rows = append(rows, m.groupHeader(groups[group]))
+		}
+	}
+	list := strings.Join(scrollRows(rows, selectedRow, maxLines, m.styles.Muted, m.text.More), "\n")
+
+	if t := m.selectedTimer(); m.showDetail && t != nil {
+		if rtl {
+			list = lipgloss.JoinHorizontal(lipgloss.Top, m.renderDetail(t), "  ", list)
+		} else {
+			list = lipgloss.JoinHorizontal(lipgloss.Top, list, "  ", m.renderDetail(t))
+		}
+	}
+	return list
+}
+
+// viewButtons is the button row.
+func (m model) viewButtons() string {
+	addButton := fmt.Sprintf("[ %s ]", m.text.Add)
+	if m.focusIndex == ADD {
+		addButton = fmt.Sprintf(m.styles.focusedButton, m.text.Add)
+	} else {
+		addButton = fmt.Sprintf(m.styles.blurredButton, m.text.Add)
+	}
+
+	startButton := fmt.Sprintf("[ %s ]", m.text.Start)
+	if m.focusIndex == START {
+		startButton = fmt.Sprintf(m.styles.focusedButton, m.text.Start)
+	} else {
+		startButton = fmt.Sprintf(m.styles.blurredButton, m.text.Start)
+	}
+
+	stopButton := fmt.Sprintf("[ %s ]", m.text.Stop)
+	if m.focusIndex == STOP {
+		stopButton = fmt.Sprintf(m.styles.focusedButton, m.text.Stop)
+	} else {
+		stopButton = fmt.Sprintf(m.styles.blurredButton, m.text.Stop)
+	}
+
+	resetButton := fmt.Sprintf("[ %s ]", m.text.Reset)
+	if m.focusIndex == RESET {
+		resetButton = fmt.Sprintf(m.styles.focusedButton, m.text.Reset)
+	} else {
+		resetButton = fmt.Sprintf(m.styles.blurredButton, m.text.Reset)
+	}
+
+	clearButton := fmt.Sprintf("[ %s ]", m.text.Clear)
+	if m.focusIndex == CLEAR {
+		clearButton = fmt.Sprintf(m.styles.focusedButton, m.text.Clear)
+	} else {
+		clearButton = fmt.Sprintf(m.styles.blurredButton, m.text.Clear)
+	}
+
+	quitButton := fmt.Sprintf("[ %s ]", m.text.Quit)
+	if m.focusIndex == QUIT {
+		quitButton = fmt.Sprintf(m.styles.focusedButton, m.text.Quit)
+	} else {
+		quitButton = fmt.Sprintf(m.styles.blurredButton, m.text.Quit)
+	}
+
+	buttons := []string{addButton, startButton, stopButton, resetButton, clearButton, quitButton}
+	for i, b := range m.cfg.Buttons {
+		if m.focusIndex == QUIT+Focus(i+1) {
+			buttons = append(buttons, fmt.Sprintf(m.styles.focusedButton, b.title()))
+		} else {
+			buttons = append(buttons, fmt.Sprintf(m.styles.blurredButton, b.title()))
+		}
+	}
+	if m.cfg.rtl() {
+		slices.Reverse(buttons)
+	}
+	return strings.Join(buttons, "  ")
+}
+
+// viewFooter is the confirmation prompt or toast, then the help line.
+func (m model) viewFooter() string {
+	var s strings.Builder
+	if m.confirming != CONFIRM_NONE {
+		s.WriteString(m.styles.Focused.Render(m.confirmPrompt))
+		s.WriteString("\n")
+	} else if m.toast != "" {
+		s.WriteString(m.toast)
+		s.WriteString("\n")
+	}
+	if m.help.ShowAll {
+		s.WriteString(m.help.View(m.keys))
+	} else {
+		s.WriteString(m.styles.Help.Render(m.text.Help))
+	}
+	if m.muted {
+		s.WriteString(m.styles.Muted.Render("  " + m.text.Muted))
+	}
+	return s.String()
+}
+
+// reverseLabel moves a trailing "label: " colon to the front so it reads
+// correctly when placed on the right of its field.
+func reverseLabel(label string) string {
+	label = strings.TrimSpace(label)
+	if trimmed, ok := strings.CutSuffix(label, ":"); ok {
+		return ":" + trimmed
+	}
+	return label
+}
+
+// renderTimer renders one row of the timer list.
+func (m model) renderTimer(t *Timer) string {
+	var s strings.Builder
+
+	switch t.Priority {
+	case HIGH:
+		s.WriteString(m.styles.HighPriority.Render("▲ "))
+	case LOW:
+		s.WriteString(m.styles.LowPriority.Render("▼ "))
+	default:
+		s.WriteString("  ")
+	}
+	s.WriteString(fmt.Sprintf("#%d: ", t.ID))
+	if t.Label != "" {
+		s.WriteString(t.Label + " — ")
+	}
+	if t.Finished {
+		msg := m.text.TimesUp
+		if t.Alarming && m.cfg.noBlink() {
+			s.WriteString(m.styles.AlarmSolid.Render(msg))
+		} else if t.Alarming && m.blink {
+			s.WriteString(m.styles.Alarm.Render(msg))
+		} else {
+			s.WriteString(msg)
+		}
+	} else if t.Queued {
+		s.WriteString(fmt.Sprintf("%s %s", t.Duration, m.text.Queued))
+	} else if t.CountUp {
+		status := ""
+		if !t.Running {
+			status = " " + m.text.Paused
+		}
+		s.WriteString(fmt.Sprintf("⏱ %s %s%s", t.Elapsed.Round(time.Second), m.text.Elapsed, status))
+	} else {
+		status := ""
+		if t.Waiting {
+			status = " " + m.text.Waiting
+		} else if !t.Running {
+			status = " " + m.text.Paused
+		}
+		if t.Finishing {
+			s.WriteString(m.text.Finishing + " ")
+		}
+		remaining := m.remainingStyle(t.Remaining)
+		if m.showElapsed {
+			s.WriteString(fmt.Sprintf("%s %s / ", (t.Duration - t.Remaining).Round(time.Second), m.text.Elapsed))
+		}
+		if m.showWords {
+			s.WriteString(remaining.Render(humanizeRemaining(t.Remaining, m.text)) + status)
+		} else {
+			s.WriteString(remaining.Render(fmt.Sprintf("%s %s", formatRemaining(t.Remaining), m.text.Remaining)) + status)
+		}
+		if t.Running {
+			// Paused timers have no end time until they resume
+			s.WriteString(m.styles.Muted.Render(fmt.Sprintf(" (%s %s)", m.text.Ends, t.Deadline.Format("15:04"))))
+		}
+		if t.PauseAt > 0 && !t.CheckpointHit {
+			s.WriteString(m.styles.Muted.Render(fmt.Sprintf(" ⏸ %s", t.PauseAt)))
+		}
+		if t.Repeat > 0 {
+			s.WriteString(m.styles.Muted.Render(fmt.Sprintf(" (%d %s)", t.Repeat, m.text.Left)))
+		} else if t.Repeat < 0 {
+			s.WriteString(m.styles.Muted.Render(" " + m.text.Repeating))
+		}
+	}
+	if !t.CountUp {
+		s.WriteString(" " + m.timerBar.ViewAs(t.progress()))
+	}
+	if !m.referenceTime.IsZero() && t.Running && !t.CountUp {
+		s.WriteString(m.styles.Muted.Render(" " + relativeToReference(m.nowFunc().Add(t.Remaining), m.referenceTime)))
+	}
+	if m.cfg.ShowStarted {
+		s.WriteString(m.styles.Muted.Render(fmt.Sprintf(" · %s %s", m.text.Started, t.CreatedAt.Format("15:04"))))
+	}
+	return s.String()
+}
+
+// Countdowns turn from green to yellow under soonThreshold and to red under
+// imminentThreshold.
+const (
+	soonThreshold     = time.Minute
+	imminentThreshold = 10 * time.Second
+)
+
+func (m model) remainingStyle(d time.Duration) lipgloss.Style {
+	switch {
+	case d < imminentThreshold:
+		return m.styles.Imminent
+	case d < soonThreshold:
+		return m.styles.Soon
+	}
+	return m.styles.Plenty
+}
+
+// minHeight is the fewest rows the full view is drawn in; below it, or
+// narrower than the button row, viewTooSmall takes over.
+const minHeight = 10
+
+func (m model) tooSmall() bool {
+	if m.inline || m.width == 0 {
+		return false // No size yet, or inline mode, which never centers
+	}
+	return m.height < minHeight || m.width < lipgloss.Width(m.viewButtons())
+}
+
+// viewTooSmall is the input and a one-line status, cut to the width.
+func (m model) viewTooSmall() string {
+	status := m.summary()
+	if m.anyAlarming() {
+		status = m.styles.Alarm.Render(m.text.TimesUp) + " " + status
+	}
+	lines := []string{
+		m.styles.Input.Render(m.text.NewTimer) + m.textInput.View(),
+		status,
+	}
+	if m.height > len(lines) {
+		lines = append(lines, m.styles.Muted.Render(m.text.TooSmall))
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, m.width, "…")
+	}
+	return strings.Join(lines[:min(len(lines), max(1, m.height))], "\n")
+}
+
+// viewAlarms is a "3 alarms!" header while more than one timer rings.
+func (m model) viewAlarms() string {
+	if n := m.alarmCount(); n > 1 {
+		return m.styles.Alarm.Render(fmt.Sprintf("%d %s", n, m.text.Alarms))
+	}
+	return ""
+}
+
+// viewCompact fits everything on as few lines as possible: the input, then
+// every timer on one line, then any prompt or toast. Actions are keys only,
+// acting on the timer marked ">".
+func (m model) viewCompact() string {
+	var items []string
+	for _, t := range m.displayTimers() {
+		name := fmt.Sprintf("#%d", t.ID)
+		if t.Label != "" {
+			name += " " + t.Label
+		}
+		var item string
+		switch {
+		case t.Finished:
+			item = name + " " + m.text.TimesUp
+		case t.CountUp:
+			item = fmt.Sprintf("%s ⏱ %s", name, t.Elapsed.Round(time.Second))
+		default:
+			item = fmt.Sprintf("%s %s", name, formatRemaining(t.Remaining))
+		}
+		if !t.Finished && !t.Running {
+			item += " ⏸"
+		}
+		selected := m.focusIndex == LIST && t.ID == m.selectedID
+		if selected {
+			item = "> " + item
+		}
+		switch {
+		case t.Alarming && m.cfg.noBlink():
+			item = m.styles.AlarmSolid.Render(item)
+		case t.Alarming && m.blink:
+			item = m.styles.Alarm.Render(item)
+		case selected:
+			item = m.styles.Focused.Render(item)
+		}
+		items = append(items, item)
+	}
+	timers := m.styles.Muted.Render(m.text.NoTimers)
+	if len(items) > 0 {
+		timers = strings.Join(items, ", ")
+	}
+
+	lines := []string{m.styles.Input.Render(m.text.NewTimer) + m.textInput.View(), timers}
+	if m.confirming != CONFIRM_NONE {
+		lines = append(lines, m.styles.Focused.Render(m.confirmPrompt))
+	} else if m.inputError != "" {
+		lines = append(lines, m.styles.Error.Render("✗ "+m.inputError))
+	} else if m.toast != "" {
+		lines = append(lines, m.toast)
+	}
+	if m.width > 0 {
+		for i, line := range lines {
+			lines[i] = ansi.Truncate(line, m.width, "…")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatRemaining rounds d to the second, or to tenths ("3.4s") in the
+// final countdown.
+func formatRemaining(d time.Duration) string {
+	if d < finalCountdown {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
+
+// viewTotals adds up the time left on every unfinished timer, e.g.
+// "3 timers, 12m4s total remaining · 1 finished (1 ringing)".
+func (m model) viewTotals() string {
+	if len(m.timers) == 0 {
+		return ""
+	}
+	var total time.Duration
+	active, finished, alarming := 0, 0, 0
+	for _, t := range m.timers {
+		switch {
+		case t.Finished:
+			finished++
+			if t.Alarming {
+				alarming++
+			}
+		case !t.CountUp:
+			active++
+			total += t.Remaining
+		}
+	}
+
+	s := fmt.Sprintf(m.text.Totals, active, total.Round(time.Second))
+	if finished > 0 {
+		s += fmt.Sprintf(m.text.TotalsFinished, finished)
+		if alarming > 0 {
+			s += fmt.Sprintf(m.text.TotalsRinging, alarming)
+		}
+	}
+	return m.styles.Muted.Render(s)
+}
+
+// scrollRows cuts rows down to maxLines, keeping row sel in view, with an
+// "↑ N more" / "↓ N more" line where rows are hidden, more being the
+// "%d more" layout.
+func scrollRows(rows []string, sel, maxLines int, muted lipgloss.Style, more string) []string {
+	if maxLines <= 0 || len(rows) <= maxLines {
+		return rows
+	}
+	visible := max(1, maxLines-2) // Room for both markers
+	start := min(max(0, sel-visible+1), len(rows)-visible)
+
+	var out []string
+	if start > 0 {
+		out = append(out, muted.Render("↑ "+fmt.Sprintf(more, start)))
+	}
+	out = append(out, rows[start:start+visible]...)
+	if below := len(rows) - start - visible; below > 0 {
+		out = append(out, muted.Render("↓ "+fmt.Sprintf(more, below)))
+	}
+	return out
+}
+
+// sectionName is the translated name of a grouped-view section.
+func (m model) sectionName(s Section) string {
+	switch s {
+	case SECTION_RUNNING:
+		return m.text.SectionRunning
+	case SECTION_PAUSED:
+		return m.text.SectionPaused
+	}
+	return m.text.SectionFinished
+}
+
+// sectionHeader renders a grouped-view header, e.g. "▾ Running (2)".
+func (m model) sectionHeader(s Section) string {
+	count := 0
+	for _, t := range m.timers {
+		if t.section() == s {
+			count++
+		}
+	}
+	arrow := "▾"
+	if m.collapsed[s] {
+		arrow = "▸"
+	}
+	return m.styles.Header.Render(fmt.Sprintf("%s %s (%d)", arrow, m.sectionName(s), count))
+}
+
+// groupHeader renders a by-group header with the time its countdowns have
+// left between them, e.g. "▾ Work (2, 25m0s left)".
+func (m model) groupHeader(name string) string {
+	count := 0
+	var left time.Duration
+	for _, t := range m.timers {
+		if t.Group != name {
+			continue
+		}
+		count++
+		if !t.Finished && !t.CountUp {
+			left += t.Remaining
+		}
+	}
+	arrow := "▾"
+	if m.collapsedGroups[name] {
+		arrow = "▸"
+	}
+	if name == "" {
+		name = m.text.Ungrouped
+	}
+	return m.styles.Header.Render(fmt.Sprintf("%s %s (%d, %s %s)", arrow, name, count, left.Round(time.Second), m.text.Left))
+}
+
+// humanizeRemaining buckets d into a phrase like "about 5 minutes left",
+// for when precision matters less than a quick read.
+func humanizeRemaining(d time.Duration, text uiStrings) string {
+	switch {
+	case d < time.Minute:
+		return text.UnderMinute
+	case d < 90*time.Second:
+		return text.AboutMinute
+	case d < 55*time.Minute:
+		return fmt.Sprintf(text.AboutMinutes, int(d.Round(time.Minute)/time.Minute))
+	case d < 90*time.Minute:
+		return text.AboutHour
+	default:
+		return fmt.Sprintf(text.AboutHours, int(d.Round(time.Hour)/time.Hour))
+	}
+}
+
+// relativeToReference formats at as "T+1m30s" or "T-45s" from ref.
+func relativeToReference(at, ref time.Time) string {
+	d := at.Sub(ref).Round(time.Second)
+	if d < 0 {
+		return "T-" + (-d).String()
+	}
+	return "T+" + d.String()
+}
+
+// renderDetail renders every field of t in a bordered box for the detail pane.
+func (m model) renderDetail(t *Timer) string {
+	d := m.text.Detail
+	var lines []string
+	field := func(name, value string) {
+		lines = append(lines, m.styles.Muted.Render(fmt.Sprintf("%-10s", name))+" "+value)
+	}
+
+	field(d.ID, fmt.Sprintf("#%d", t.ID))
+	if t.Label != "" {
+		field(d.Label, t.Label)
+	}
+	if t.Group != "" {
+		field(d.Group, t.Group)
+	}
+	field(d.Priority, t.Priority.String())
+	if t.CountUp {
+		field(d.Elapsed, t.Elapsed.Round(time.Second).String())
+	} else {
+		field(d.Duration, t.Duration.String())
+		field(d.Remaining, t.Remaining.Round(time.Second).String())
+	}
+
+	status := d.Running
+	switch {
+	case t.Finished:
+		status = d.Finished
+	case t.Queued:
+		status = d.Queued
+	case t.Waiting:
+		status = d.Waiting
+	case !t.Running:
+		status = d.Paused
+	}
+	field(d.Status, status)
+	field(d.Created, t.CreatedAt.Format("15:04:05"))
+	if !t.RunStart.IsZero() {
+		field(d.ThisRun, t.RunStart.Format("15:04:05"))
+	}
+	if t.Running && !t.CountUp {
+		field(d.Finishes, m.nowFunc().Add(t.Remaining).Format("15:04:05"))
+	}
+	if t.Command != "" {
+		field(d.Command, t.Command)
+	}
+	if t.PauseAt > 0 {
+		checkpoint := t.PauseAt.String()
+		if t.CheckpointHit {
+			checkpoint += " " + d.Reached
+		}
+		field(d.Checkpoint, checkpoint)
+	}
+	if t.Pomodoro {
+		field(d.Phase, pomodoroPhase(t))
+	}
+	switch {
+	case t.Repeat < 0:
+		field(d.Repeat, d.Forever)
+	case t.Repeat > 0:
+		field(d.Repeat, fmt.Sprintf(d.More, t.Repeat))
+	}
+	sound := m.timerSound(t)
+	if sound == "" {
+		sound = d.SystemDefault
+	}
+	field(d.Sound, sound)
+
+	return m.styles.Detail.Render(strings.Join(lines, "\n"))
+}
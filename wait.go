@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// runWait is the non-interactive --wait mode: an inline countdown on one
+// line, then the alarm, then exit. Meant for shell one-liners.
+func runWait(d time.Duration, text uiStrings, soundFile string, volume int) {
+	deadline := time.Now().Add(d)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		remaining := time.Until(deadline).Round(time.Second)
+		if remaining <= 0 {
+			break
+		}
+		// \r and the erase-line escape keep the countdown on a single line
+		fmt.Printf("\r\033[K%s %s", remaining, text.Remaining)
+		<-ticker.C
+	}
+	fmt.Printf("\r\033[K%s\n", text.TimesUp)
+	playSound(context.Background(), soundFile, volume)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const webhookTimeout = 5 * time.Second
+
+// webhookPayload is the JSON body posted when a timer finishes.
+type webhookPayload struct {
+	ID       int       `json:"id"`
+	Label    string    `json:"label,omitempty"`
+	Duration string    `json:"duration"`
+	Priority string    `json:"priority"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// webhookResultMsg reports a failed webhook delivery.
+type webhookResultMsg struct {
+	ID  int
+	Err error
+}
+
+func postWebhook(url string, t *Timer, finished time.Time) tea.Cmd {
+	payload := webhookPayload{
+		ID:       t.ID,
+		Label:    t.Label,
+		Duration: t.Duration.String(),
+		Priority: t.Priority.String(),
+		Started:  t.began(),
+		Finished: finished,
+	}
+	return func() tea.Msg {
+		return webhookResultMsg{ID: payload.ID, Err: sendWebhook(url, payload)}
+	}
+}
+
+func sendWebhook(url string, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return nil
+}